@@ -56,7 +56,7 @@ var filterAddCmd = &cobra.Command{
 		if color, err := cmd.Flags().GetInt("color"); err != nil {
 			return err
 		} else {
-			opts.Color = color
+			opts.Color = todoist.Color(color)
 		}
 		if order, err := cmd.Flags().GetInt("order"); err != nil {
 			return err
@@ -133,7 +133,7 @@ var filterUpdateCmd = &cobra.Command{
 			return err
 		} else {
 			if cmd.Flags().Changed("color") {
-				filter.Color = color
+				filter.Color = todoist.Color(color)
 			}
 		}
 		if order, err := cmd.Flags().GetInt("order"); err != nil {
@@ -181,7 +181,7 @@ var filterDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "delete filter",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require filter id to delete")
 			}
@@ -82,7 +82,7 @@ var itemAddCmd = &cobra.Command{
 		if err != nil {
 			return errors.New("invalid priority")
 		}
-		opts.Priority = priority
+		opts.Priority = todoist.Priority(priority)
 		item, err := todoist.NewItem(content, &opts)
 		if err != nil {
 			return err
@@ -164,7 +164,7 @@ var itemUpdateCmd = &cobra.Command{
 		if err != nil {
 			return errors.New("invalid priority")
 		}
-		item.Priority = priority
+		item.Priority = todoist.Priority(priority)
 
 		if _, err = client.Item.Update(*item); err != nil {
 			return err
@@ -191,7 +191,7 @@ var itemDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "delete items",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) != 1 {
 				return fmt.Errorf("require one item id")
 			}
@@ -284,7 +284,7 @@ var itemCompleteCmd = &cobra.Command{
 	Use:   "complete",
 	Short: "complete items",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) != 1 {
 				return fmt.Errorf("require one item id")
 			}
@@ -293,7 +293,7 @@ var itemCompleteCmd = &cobra.Command{
 				return err
 			}
 			// FIXME: support date_completed option
-			date := todoist.Time{time.Now().UTC()}
+			date := todoist.Time{Time: time.Now().UTC()}
 			return client.Item.Complete(id, date, true)
 		}); err != nil {
 			return err
@@ -307,7 +307,7 @@ var itemUncompleteCmd = &cobra.Command{
 	Use:   "uncomplete",
 	Short: "uncomplete items",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) != 1 {
 				return fmt.Errorf("require one item id")
 			}
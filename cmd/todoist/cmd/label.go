@@ -48,7 +48,7 @@ var labelAddCmd = &cobra.Command{
 		if color, err := cmd.Flags().GetInt("color"); err != nil {
 			return err
 		} else {
-			opts.Color = color
+			opts.Color = todoist.Color(color)
 		}
 		if order, err := cmd.Flags().GetInt("order"); err != nil {
 			opts.ItemOrder = order
@@ -115,7 +115,7 @@ var labelUpdateCmd = &cobra.Command{
 			return err
 		} else {
 			if cmd.Flags().Changed("color") {
-				label.Color = color
+				label.Color = todoist.Color(color)
 			}
 		}
 		if order, err := cmd.Flags().GetInt("order"); err != nil {
@@ -163,7 +163,7 @@ var labelDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "delete label",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require label id to delete")
 			}
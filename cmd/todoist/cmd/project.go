@@ -48,7 +48,7 @@ var projectAddCmd = &cobra.Command{
 		if color, err := cmd.Flags().GetInt("color"); err != nil {
 			return err
 		} else {
-			opts.Color = color
+			opts.Color = todoist.Color(color)
 		}
 		if parentStr, err := cmd.Flags().GetString("parent"); err != nil {
 			return err
@@ -130,7 +130,7 @@ var projectUpdateCmd = &cobra.Command{
 			return err
 		} else {
 			if cmd.Flags().Changed("color") {
-				project.Color = color
+				project.Color = todoist.Color(color)
 			}
 		}
 		if collapsed, err := cmd.Flags().GetBool("collapsed"); err != nil {
@@ -181,8 +181,7 @@ var projectUpdateCmd = &cobra.Command{
 			return err
 		} else {
 			if cmd.Flags().Changed("order") {
-				project.ChildOrder = order
-				if err = client.Project.Reorder([]todoist.Project{*project}); err != nil {
+				if err = client.Project.Reorder([]todoist.ProjectOrder{{ID: project.ID, ChildOrder: order}}); err != nil {
 					return err
 				}
 			}
@@ -208,7 +207,7 @@ var projectDeleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "delete project",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require project id to delete")
 			}
@@ -242,7 +241,7 @@ var projectArchiveCmd = &cobra.Command{
 	Use:   "archive [id]",
 	Short: "archive project",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require project id to archive")
 			}
@@ -261,7 +260,7 @@ var projectUnarchiveCmd = &cobra.Command{
 	Use:   "unarchive [id]",
 	Short: "unarchive project",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if err := util.AutoCommit(func(client todoist.Client, ctx context.Context) error {
+		if err := util.AutoCommit(func(client *todoist.Client, ctx context.Context) error {
 			if len(args) == 0 {
 				return errors.New("require project id to un-archive")
 			}
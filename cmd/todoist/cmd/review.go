@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/kobtea/go-todoist/cmd/util"
@@ -26,7 +27,7 @@ var reviewCmd = &cobra.Command{
 		if err != nil {
 			return err
 		}
-		completed, err := client.Completed.GetAll()
+		completed, err := client.Completed.GetAll(context.Background())
 		if err != nil {
 			return err
 		}
@@ -20,7 +20,7 @@ var syncCmd = &cobra.Command{
 		if err = client.FullSync(context.Background(), []todoist.Command{}); err != nil {
 			return err
 		}
-		fmt.Printf("update sync token: %s", client.SyncToken)
+		fmt.Printf("update sync token: %s", client.SyncToken())
 		return nil
 	},
 }
@@ -30,21 +30,16 @@ func resolveToken() string {
 }
 
 func NewClient() (*todoist.Client, error) {
-	return todoist.NewClient(
-		"",
-		resolveToken(),
-		"*",
-		"",
-		nil)
+	return todoist.NewClient(resolveToken())
 }
 
-func AutoCommit(f func(client todoist.Client, ctx context.Context) error) error {
+func AutoCommit(f func(client *todoist.Client, ctx context.Context) error) error {
 	client, err := NewClient()
 	if err != nil {
 		return err
 	}
 	ctx := context.Background()
-	if err = f(*client, ctx); err != nil {
+	if err = f(client, ctx); err != nil {
 		return err
 
 	}
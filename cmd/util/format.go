@@ -86,7 +86,7 @@ func ItemTableString(items []todoist.Item, relations todoist.ItemRelations, f fu
 		rows = append(rows, []todoist.ColorStringer{
 			todoist.NewNoColorString(i.ID.String()),
 			f(i),
-			todoist.NewNoColorString(strconv.Itoa(i.Priority)),
+			todoist.NewNoColorString(strconv.Itoa(int(i.Priority))),
 			project,
 			labels,
 			todoist.NewNoColorString(i.Content),
@@ -0,0 +1,122 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	EventTypeAdded       = "added"
+	EventTypeUpdated     = "updated"
+	EventTypeDeleted     = "deleted"
+	EventTypeCompleted   = "completed"
+	EventTypeUncompleted = "uncompleted"
+	EventTypeArchived    = "archived"
+	EventTypeUnarchived  = "unarchived"
+	EventTypeShared      = "shared"
+	EventTypeLeft        = "left"
+)
+
+const (
+	ObjectTypeItem    = "item"
+	ObjectTypeNote    = "note"
+	ObjectTypeProject = "project"
+)
+
+// ActivityEvent is an event in the activity log.
+// ExtraData depends on the object type and the event type, e.g. content of items.
+type ActivityEvent struct {
+	ID              ID                     `json:"id"`
+	ObjectType      string                 `json:"object_type"`
+	ObjectID        ID                     `json:"object_id"`
+	EventType       string                 `json:"event_type"`
+	EventDate       Time                   `json:"event_date"`
+	ParentProjectID ID                     `json:"parent_project_id"`
+	ParentItemID    ID                     `json:"parent_item_id"`
+	InitiatorID     ID                     `json:"initiator_id"`
+	ExtraData       map[string]interface{} `json:"extra_data"`
+}
+
+// Activity is a page of the activity log. Count is the number of events matched by the filter.
+type Activity struct {
+	Events []ActivityEvent `json:"events"`
+	Count  int             `json:"count"`
+}
+
+type ActivityOpts struct {
+	ObjectType      string
+	ObjectID        ID
+	EventType       string
+	ParentProjectID ID
+	ParentItemID    ID
+	InitiatorID     ID
+	Since           time.Time
+	Until           time.Time
+	// Limit defaults to the server default.
+	Limit  int
+	Offset int
+}
+
+func (o ActivityOpts) values() url.Values {
+	const layout = "2006-01-02T15:04"
+	values := url.Values{}
+	if len(o.ObjectType) != 0 {
+		values.Set("object_type", o.ObjectType)
+	}
+	if !o.ObjectID.IsZero() {
+		values.Set("object_id", o.ObjectID.String())
+	}
+	if len(o.EventType) != 0 {
+		values.Set("event_type", o.EventType)
+	}
+	if !o.ParentProjectID.IsZero() {
+		values.Set("parent_project_id", o.ParentProjectID.String())
+	}
+	if !o.ParentItemID.IsZero() {
+		values.Set("parent_item_id", o.ParentItemID.String())
+	}
+	if !o.InitiatorID.IsZero() {
+		values.Set("initiator_id", o.InitiatorID.String())
+	}
+	if !o.Since.IsZero() {
+		values.Set("since", o.Since.UTC().Format(layout))
+	}
+	if !o.Until.IsZero() {
+		values.Set("until", o.Until.UTC().Format(layout))
+	}
+	if o.Limit > 0 {
+		values.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Offset > 0 {
+		values.Set("offset", strconv.Itoa(o.Offset))
+	}
+	return values
+}
+
+// ActivityClient encapsulate client operations for the activity log.
+type ActivityClient struct {
+	*Client
+}
+
+// Get returns a page of the activity log filtered by opts.
+func (c *ActivityClient) Get(ctx context.Context, opts ActivityOpts) (*Activity, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, pathActivityGet, opts.values())
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if (res.StatusCode / 100) != 2 {
+		return nil, newAPIError(res)
+	}
+	var out Activity
+	if err = c.decodeBody(res, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,54 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestActivityClient_Get(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/activity/get" {
+			t.Errorf("Expect %s, but got %s", "/activity/get", r.URL.Path)
+		}
+		for k, v := range map[string]string{
+			"object_type":       "item",
+			"event_type":        "completed",
+			"parent_project_id": "10",
+			"since":             "2020-01-01T00:00",
+			"limit":             "50",
+		} {
+			if actual := r.FormValue(k); actual != v {
+				t.Errorf("Expect %s=%s, but got %s", k, v, actual)
+			}
+		}
+		w.Write([]byte(`{"count": 1, "events": [{
+			"id": 1, "object_type": "item", "object_id": 100, "event_type": "completed",
+			"event_date": "2020-01-02T03:04:05Z", "parent_project_id": 10, "initiator_id": null,
+			"extra_data": {"content": "Buy milk", "client": "web", "note_count": 2}
+		}]}`))
+	})
+	defer teardown()
+
+	activity, err := client.Activity.Get(context.Background(), ActivityOpts{
+		ObjectType:      ObjectTypeItem,
+		EventType:       EventTypeCompleted,
+		ParentProjectID: "10",
+		Since:           time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Limit:           50,
+	})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if activity.Count != 1 || len(activity.Events) != 1 {
+		t.Fatalf("Unexpect activity: %v", activity)
+	}
+	event := activity.Events[0]
+	if event.ObjectID != "100" || event.EventType != EventTypeCompleted || event.EventDate.IsZero() {
+		t.Errorf("Unexpect event: %v", event)
+	}
+	if event.ExtraData["content"] != "Buy milk" {
+		t.Errorf("Expect %s, but got %v", "Buy milk", event.ExtraData["content"])
+	}
+}
@@ -0,0 +1,87 @@
+// Package auth implements the OAuth flow of Todoist to obtain access tokens of users.
+//
+// Redirect the user to AuthorizationURL with a random state, and call ExchangeCode with
+// the code given to the redirect URL of the app. Validating that the state given to the
+// redirect URL is the one passed to AuthorizationURL is the caller's responsibility.
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// BaseURL is the base of the OAuth endpoints.
+var BaseURL = "https://todoist.com/oauth"
+
+// Scopes of access tokens. Join them with "," to request some at once.
+const (
+	ScopeTaskAdd       = "task:add"
+	ScopeDataRead      = "data:read"
+	ScopeDataReadWrite = "data:read_write"
+	ScopeDataDelete    = "data:delete"
+	ScopeProjectDelete = "project:delete"
+)
+
+// TokenResponse is the access token issued by ExchangeCode.
+type TokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+}
+
+// ExchangeError is returned when the server rejects the exchange, e.g. with a wrong client secret.
+// Body is the raw response body to diagnose the configuration of the app.
+type ExchangeError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *ExchangeError) Error() string {
+	return fmt.Sprintf("failed to exchange code, status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// AuthorizationURL returns the URL to redirect the user to authorize the app.
+// state should be an unguessable value, which is given back to the redirect URL.
+func AuthorizationURL(clientID, scope, state string) string {
+	values := url.Values{
+		"client_id": {clientID},
+		"scope":     {scope},
+		"state":     {state},
+	}
+	return BaseURL + "/authorize?" + values.Encode()
+}
+
+// ExchangeCode exchanges the code given to the redirect URL for an access token.
+func ExchangeCode(ctx context.Context, clientID, clientSecret, code string) (*TokenResponse, error) {
+	values := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"code":          {code},
+	}
+	req, err := http.NewRequest(http.MethodPost, BaseURL+"/access_token", strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	res, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(res.Body)
+		return nil, &ExchangeError{StatusCode: res.StatusCode, Body: string(body)}
+	}
+	var out TokenResponse
+	if err = json.NewDecoder(res.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if len(out.AccessToken) == 0 {
+		return nil, fmt.Errorf("failed to exchange code, no access token in the response")
+	}
+	return &out, nil
+}
@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestAuthorizationURL(t *testing.T) {
+	u, err := url.Parse(AuthorizationURL("id", ScopeDataReadWrite+","+ScopeDataDelete, "state"))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if u.Path != "/oauth/authorize" {
+		t.Errorf("Expect %s, but got %s", "/oauth/authorize", u.Path)
+	}
+	q := u.Query()
+	if q.Get("client_id") != "id" || q.Get("scope") != "data:read_write,data:delete" || q.Get("state") != "state" {
+		t.Errorf("Unexpect query: %s", u.RawQuery)
+	}
+}
+
+func TestExchangeCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/access_token" {
+			t.Errorf("Expect %s, but got %s", "/access_token", r.URL.Path)
+		}
+		if r.FormValue("client_secret") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "bad_client_secret"}`))
+			return
+		}
+		if r.FormValue("client_id") != "id" || r.FormValue("code") != "code" {
+			t.Errorf("Unexpect form: %v", r.Form)
+		}
+		w.Write([]byte(`{"access_token": "token", "token_type": "Bearer"}`))
+	}))
+	defer server.Close()
+	defer func(u string) { BaseURL = u }(BaseURL)
+	BaseURL = server.URL
+
+	res, err := ExchangeCode(context.Background(), "id", "secret", "code")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if res.AccessToken != "token" || res.TokenType != "Bearer" {
+		t.Errorf("Unexpect response: %v", res)
+	}
+
+	_, err = ExchangeCode(context.Background(), "id", "wrong", "code")
+	var e *ExchangeError
+	if !errors.As(err, &e) || e.StatusCode != http.StatusBadRequest || e.Body != `{"error": "bad_client_secret"}` {
+		t.Errorf("Expect exchange error, but got %v", err)
+	}
+}
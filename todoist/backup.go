@@ -0,0 +1,80 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Backup is an automatic backup of the user's data.
+// Version is the time of the backup in UTC, e.g. "2020-01-02 03:04".
+type Backup struct {
+	Version string `json:"version"`
+	URL     string `json:"url"`
+}
+
+// Time returns the time of the backup.
+func (b Backup) Time() (time.Time, error) {
+	return time.Parse("2006-01-02 15:04", b.Version)
+}
+
+// BackupExpiredError is returned when the backup is not found any more.
+type BackupExpiredError struct {
+	Backup Backup
+}
+
+func (e *BackupExpiredError) Error() string {
+	return fmt.Sprintf("backup %s is expired", e.Backup.Version)
+}
+
+// BackupClient encapsulate client operations for backups.
+type BackupClient struct {
+	*Client
+}
+
+// List returns the backups available to download.
+func (c *BackupClient) List(ctx context.Context) ([]Backup, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, pathBackupsGet, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if (res.StatusCode / 100) != 2 {
+		return nil, newAPIError(res)
+	}
+	var out []Backup
+	if err = c.decodeBody(res, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Download writes the zip file of the backup to w.
+// Nothing is written unless the server responds the file.
+func (c *BackupClient) Download(ctx context.Context, b Backup, w io.Writer) error {
+	req, err := http.NewRequest(http.MethodGet, b.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("User-Agent", c.userAgent)
+	res, err := c.do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	switch {
+	case res.StatusCode == http.StatusNotFound:
+		return &BackupExpiredError{Backup: b}
+	case (res.StatusCode / 100) != 2:
+		return newAPIError(res)
+	}
+	_, err = io.Copy(w, res.Body)
+	return err
+}
@@ -0,0 +1,60 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestBackupClient_Download(t *testing.T) {
+	var baseURL string
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/backups/get":
+			w.Write([]byte(`[
+				{"version": "2020-01-02 03:04", "url": "` + baseURL + `/download/1.zip"},
+				{"version": "2019-12-01 03:04", "url": "` + baseURL + `/download/expired.zip"}
+			]`))
+		case "/download/1.zip":
+			if auth := r.Header.Get("Authorization"); auth != "Bearer test-token" {
+				t.Errorf("Expect %s, but got %s", "Bearer test-token", auth)
+			}
+			w.Write([]byte("zip"))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer teardown()
+	baseURL = client.URL.String()
+
+	backups, err := client.Backup.List(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("Expect %d backups, but got %d", 2, len(backups))
+	}
+	if v, err := backups[0].Time(); err != nil || v.Hour() != 3 {
+		t.Errorf("Unexpect time: %s, %v", v, err)
+	}
+
+	var buf bytes.Buffer
+	if err = client.Backup.Download(context.Background(), backups[0], &buf); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if buf.String() != "zip" {
+		t.Errorf("Expect %s, but got %s", "zip", buf.String())
+	}
+
+	buf.Reset()
+	err = client.Backup.Download(context.Background(), backups[1], &buf)
+	var expiredErr *BackupExpiredError
+	if !errors.As(err, &expiredErr) {
+		t.Errorf("Expect *BackupExpiredError, but got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expect nothing to be written, but got %s", buf.String())
+	}
+}
@@ -0,0 +1,83 @@
+package todoist
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestItemCache_Index(t *testing.T) {
+	cache := &itemCache{cache: &[]Item{}}
+	for i := 1; i <= 3; i++ {
+		item := Item{Content: strconv.Itoa(i)}
+		item.ID = ID(strconv.Itoa(i))
+		cache.store(item)
+	}
+	removed := Item{}
+	removed.ID = "2"
+	cache.remove(removed)
+	cache.replaceTempIDs(map[ID]ID{"3": "30"})
+	updated := Item{Content: "updated"}
+	updated.ID = "1"
+	cache.store(updated)
+	cache.store(Item{Entity: Entity{ID: "4"}, Content: "4"})
+	cache.store(Item{Entity: Entity{ID: "4", IsDeleted: true}})
+
+	tests := []struct {
+		id      ID
+		content string
+	}{
+		{"1", "updated"},
+		{"2", ""},
+		{"3", ""},
+		{"30", "3"},
+		{"4", ""},
+	}
+	for _, test := range tests {
+		item := cache.resolve(test.id)
+		switch {
+		case len(test.content) == 0 && item != nil:
+			t.Errorf("Expect nil, but got %v", item)
+		case len(test.content) != 0 && (item == nil || item.Content != test.content):
+			t.Errorf("Expect %s, but got %v", test.content, item)
+		}
+	}
+}
+
+func newBenchmarkItems(n int) []Item {
+	items := make([]Item, n)
+	for i := range items {
+		items[i].ID = ID(strconv.Itoa(i + 1))
+	}
+	return items
+}
+
+func BenchmarkItemCache_Resolve(b *testing.B) {
+	items := newBenchmarkItems(10000)
+	cache := &itemCache{cache: &items, index: indexItems(items)}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.resolve(ID(strconv.Itoa(i%10000 + 1)))
+	}
+}
+
+// BenchmarkItemCache_ResolveScan resolves without the index for comparison.
+func BenchmarkItemCache_ResolveScan(b *testing.B) {
+	items := newBenchmarkItems(10000)
+	cache := &itemCache{cache: &items}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		cache.resolve(ID(strconv.Itoa(i%10000 + 1)))
+	}
+}
+
+// BenchmarkItemCache_Store updates cached items and adds new ones, as syncs of large accounts do.
+func BenchmarkItemCache_Store(b *testing.B) {
+	items := newBenchmarkItems(10000)
+	cache := &itemCache{cache: &items, index: indexItems(items)}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		item := Item{Content: "updated"}
+		item.ID = ID(strconv.Itoa(i%20000 + 1))
+		cache.store(item)
+	}
+}
@@ -1,95 +1,186 @@
 package todoist
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 type Client struct {
 	URL        *url.URL
 	HTTPClient *http.Client
 	Token      string
-	SyncToken  string
-	CacheDir   string
-	syncState  *SyncState
-	Logger     *log.Logger
-	Completed  *CompletedClient
-	Filter     *FilterClient
-	Item       *ItemClient
-	Label      *LabelClient
-	Project    *ProjectClient
-	Relation   *RelationClient
-	Note       *NoteClient
-	queue      []Command
-}
-
-func NewClient(endpoint, token, sync_token, cache_dir string, logger *log.Logger) (*Client, error) {
-	if len(endpoint) == 0 {
-		endpoint = "https://api.todoist.com/sync/v8"
-	}
-	parsed_endpoint, err := url.ParseRequestURI(endpoint)
+	syncToken  string
+	// dayOrdersTimestamp is the version of the day orders by the last sync.
+	dayOrdersTimestamp string
+	userAgent          string
+	// strictDecoding rejects unknown fields in responses.
+	strictDecoding bool
+	// discardOnClose makes Close discard the queue instead of committing it.
+	discardOnClose bool
+	// resourceTypes limits the resources fetched by syncs. Empty means all.
+	resourceTypes []string
+	// requestHook observes requests if set.
+	requestHook RequestHook
+	// etags caches responses of GETs by ETag if set.
+	etags *etagCache
+	// endpointURLs overrides URL for the endpoint families.
+	endpointURLs map[Endpoint]*url.URL
+	// requestTimeout limits requests without deadline if positive.
+	requestTimeout time.Duration
+	// cacheMode is whether queued commands change the caches.
+	cacheMode CacheMode
+	CacheDir  string
+	syncState *SyncState
+	Logger    *log.Logger
+	// RetryPolicy is applied to requests. nil disables retries. See WithRetryPolicy.
+	RetryPolicy  *RetryPolicy
+	Activity     *ActivityClient
+	Backup       *BackupClient
+	Collaborator *CollaboratorClient
+	Completed    *CompletedClient
+	Filter       *FilterClient
+	Item         *ItemClient
+	Label        *LabelClient
+	Project      *ProjectClient
+	Section      *SectionClient
+	Stats        *StatsClient
+	Relation     *RelationClient
+	Note         *NoteClient
+	Reminder     *ReminderClient
+	user         *userCache
+	queue        []Command
+	// failed is the commands rejected by the last commit.
+	failed   []Command
+	tempIDs  map[ID]ID
+	notifier *notifier
+	closed   bool
+	// mu guards queue, failed, tempIDs, syncToken, dayOrdersTimestamp and closed.
+	mu sync.Mutex
+	// syncMu serializes syncs.
+	syncMu sync.Mutex
+}
+
+// NewClient returns a client for the given API token, configured by the options.
+func NewClient(token string, opts ...Option) (*Client, error) {
+	o := options{
+		baseURL:     DefaultBaseURL,
+		httpClient:  http.DefaultClient,
+		userAgent:   defaultUserAgent,
+		cacheDir:    "$HOME/.go-todoist",
+		retryPolicy: DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	parsedURL, err := url.ParseRequestURI(o.baseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	client := http.DefaultClient
+	endpointURLs := map[Endpoint]*url.URL{}
+	for endpoint, baseURL := range o.endpointURLs {
+		if endpointURLs[endpoint], err = url.ParseRequestURI(baseURL); err != nil {
+			return nil, err
+		}
+	}
 
 	if len(token) == 0 {
 		return nil, errors.New("Missing API Token")
 	}
 
-	if len(sync_token) == 0 {
-		sync_token = "*"
-	}
-
-	if len(cache_dir) == 0 {
-		cache_dir = "$HOME/.go-todoist"
-	}
-	cache_dir = os.ExpandEnv(cache_dir)
-	if _, err = os.Stat(cache_dir); err != nil {
-		if err = os.MkdirAll(cache_dir, 0755); err != nil {
+	cacheDir := os.ExpandEnv(o.cacheDir)
+	if _, err = os.Stat(cacheDir); err != nil {
+		if err = os.MkdirAll(cacheDir, 0755); err != nil {
 			return nil, err
 		}
 	}
 
+	logger := o.logger
 	if logger == nil {
 		logger = log.New(ioutil.Discard, "", log.LstdFlags)
 	}
 
 	c := &Client{
-		URL:        parsed_endpoint,
-		HTTPClient: client,
-		Token:      token,
-		SyncToken:  sync_token,
-		CacheDir:   cache_dir,
-		syncState:  &SyncState{},
-		Logger:     logger,
+		URL:            parsedURL,
+		HTTPClient:     o.httpClient,
+		Token:          token,
+		syncToken:      "*",
+		userAgent:      o.userAgent,
+		strictDecoding: o.strictDecoding,
+		discardOnClose: o.discardOnClose,
+		resourceTypes:  o.resourceTypes,
+		requestHook:    o.requestHook,
+		endpointURLs:   endpointURLs,
+		requestTimeout: o.requestTimeout,
+		cacheMode:      o.cacheMode,
+		CacheDir:       cacheDir,
+		syncState:      &SyncState{},
+		Logger:         logger,
+		RetryPolicy:    o.retryPolicy,
+		notifier:       newNotifier(),
+	}
+	if o.etagCacheSize > 0 {
+		c.etags = newETagCache(o.etagCacheSize)
 	}
 	if err = c.readCache(); err != nil {
 		c.resetState()
+		c.syncState = &SyncState{}
 	}
+	if len(o.syncToken) != 0 {
+		c.syncToken = o.syncToken
+	}
+	c.Activity = &ActivityClient{c}
+	c.Backup = &BackupClient{c}
+	cs := newCaches(c.syncState, c.notifier)
+	c.Collaborator = &CollaboratorClient{c, cs.collaborator}
 	c.Completed = &CompletedClient{c}
-	c.Filter = &FilterClient{c, &filterCache{&c.syncState.Filters}}
-	c.Item = &ItemClient{c, &itemCache{&c.syncState.Items}}
-	c.Label = &LabelClient{c, &labelCache{&c.syncState.Labels}}
-	c.Project = &ProjectClient{c, &projectCache{&c.syncState.Projects}}
+	c.Filter = &FilterClient{c, cs.filter}
+	c.Item = &ItemClient{c, cs.item}
+	c.Label = &LabelClient{c, cs.label}
+	c.Project = &ProjectClient{c, cs.project}
+	c.Section = &SectionClient{c, cs.section}
+	c.Stats = &StatsClient{c}
 	c.Relation = &RelationClient{c}
-	c.Note = &NoteClient{c, &noteCache{&c.syncState.Notes}}
+	c.Note = &NoteClient{c, cs.note}
+	c.Reminder = &ReminderClient{c, cs.reminder}
+	c.user = cs.user
 	return c, nil
 }
 
-func (c *Client) newRequest(ctx context.Context, method, spath string, values url.Values) (*http.Request, error) {
-	u := *c.URL
-	u.Path = path.Join(c.URL.Path, spath)
+// endpointURL returns the base URL of the endpoint family.
+func (c *Client) endpointURL(endpoint Endpoint) *url.URL {
+	if u, ok := c.endpointURLs[endpoint]; ok {
+		return u
+	}
+	return c.URL
+}
+
+// resolvePath returns the URL of the path under the base URL of its endpoint family.
+func (c *Client) resolvePath(p apiPath) url.URL {
+	base := c.endpointURL(p.endpoint)
+	u := *base
+	u.Path = path.Join(base.Path, p.path)
+	return u
+}
+
+// newRequest returns a request to the path under the base URL of its endpoint family.
+func (c *Client) newRequest(ctx context.Context, method string, p apiPath, values url.Values) (*http.Request, error) {
+	u := c.resolvePath(p)
 	values.Add("token", c.Token)
 
 	s := ""
@@ -110,124 +201,802 @@ func (c *Client) newRequest(ctx context.Context, method, spath string, values ur
 	if method == http.MethodPost {
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	}
+	req.Header.Set("User-Agent", c.userAgent)
 
 	req = req.WithContext(ctx)
 	return req, nil
 }
 
+// newMultipartRequest returns a POST request with the streamed multipart body.
+// The caller is responsible to include the token in the body.
+func (c *Client) newMultipartRequest(ctx context.Context, p apiPath, body io.Reader, contentType string) (*http.Request, error) {
+	u := c.resolvePath(p)
+	req, err := http.NewRequest(http.MethodPost, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("User-Agent", c.userAgent)
+	return req.WithContext(ctx), nil
+}
+
 func (c *Client) newSyncRequest(ctx context.Context, values url.Values) (*http.Request, error) {
-	return c.newRequest(ctx, http.MethodPost, "sync", values)
+	return c.newRequest(ctx, http.MethodPost, pathSync, values)
 }
 
-func decodeBody(resp *http.Response, out interface{}) error {
+// maxBodySnippet is the length of the response body included in decode errors.
+const maxBodySnippet = 200
+
+// decodeBody decodes the JSON response. Unknown fields are ignored unless WithStrictDecoding is given.
+// It returns *APIError for an error status code, *DecodeError with the body, e.g. an HTML error page,
+// and *TransportError if the body is not read.
+func (c *Client) decodeBody(resp *http.Response, out interface{}) error {
+	if (resp.StatusCode / 100) != 2 {
+		return newAPIError(resp)
+	}
 	defer resp.Body.Close()
-	decoder := json.NewDecoder(resp.Body)
-	return decoder.Decode(out)
+	var u string
+	var method string
+	if resp.Request != nil {
+		u = redactURL(resp.Request.URL)
+		method = resp.Request.Method
+	}
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return &TransportError{Method: method, URL: u, Err: err}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	if c.strictDecoding {
+		decoder.DisallowUnknownFields()
+	}
+	if err = decoder.Decode(out); err != nil {
+		return &DecodeError{URL: u, StatusCode: resp.StatusCode, Body: b, Err: err}
+	}
+	return nil
 }
 
 func (c *Client) Sync(ctx context.Context, commands []Command) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	_, err := c.sync(ctx, commands)
+	return err
+}
+
+// syncValues returns the parameters of a sync request except the token.
+func (c *Client) syncValues(commands []Command) (url.Values, error) {
 	b, err := json.Marshal(commands)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	resourceTypes := c.resourceTypes
+	if len(resourceTypes) == 0 {
+		resourceTypes = []string{"all"}
+	}
+	rt, err := json.Marshal(resourceTypes)
+	if err != nil {
+		return nil, err
+	}
+	return url.Values{
+		"sync_token":     {c.SyncToken()},
+		"resource_types": {string(rt)},
+		"commands":       {string(b)},
+	}, nil
+}
+
+// syncsResource reports whether syncs fetch the resource type.
+func (c *Client) syncsResource(resourceType string) bool {
+	if len(c.resourceTypes) == 0 {
+		return true
+	}
+	for _, t := range c.resourceTypes {
+		if t == resourceType || t == "all" {
+			return true
+		}
 	}
-	values := url.Values{
-		"sync_token":           {c.SyncToken},
-		"day_orders_timestamp": {""},
-		"resource_types":       {"[\"all\"]"},
-		"commands":             {string(b)},
+	return false
+}
+
+// sync returns the sync state along with *CommitError when the server has processed the commands.
+func (c *Client) sync(ctx context.Context, commands []Command) (*SyncState, error) {
+	values, err := c.syncValues(commands)
+	if err != nil {
+		return nil, err
 	}
 	req, err := c.newSyncRequest(ctx, values)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if (res.StatusCode / 100) != 2 {
-		return fmt.Errorf("failed to sync, status code: %d, command: %v", res.StatusCode, commands)
+		return nil, newAPIError(res)
 	}
 	var out SyncState
-	err = decodeBody(res, &out)
+	err = c.decodeBody(res, &out)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	// TODO: replace temp_id mapping
+	if values.Get("sync_token") == "*" {
+		out.FullSync = true
+	}
+	c.storeTempIDs(out.TempIDMapping)
+	c.replaceTempIDs(out.TempIDMapping)
+	commitErr := newCommitError(commands, out.SyncStatus)
+	c.settleCommands(commands, commitErr)
+	local := c.localChanges(commands)
 	c.updateState(&out)
+	// the caches keep the local changes, while the persisted state is merged from the server copies only.
+	c.restoreLocalChanges(local)
 	c.writeCache()
-	return nil
+	return &out, commitErr
 }
 
 func (c *Client) FullSync(ctx context.Context, commands []Command) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
 	c.resetState()
-	return c.Sync(ctx, commands)
+	_, err := c.sync(ctx, commands)
+	return err
 }
 
-func (c *Client) Commit(ctx context.Context) error {
-	if len(c.queue) == 0 {
+// ReloadAll replaces all caches with resources fetched by a full sync.
+// Queued commands are neither sent nor discarded.
+func (c *Client) ReloadAll(ctx context.Context) error {
+	return c.FullSync(ctx, []Command{})
+}
+
+// SyncSummary is the number of the resources loaded by a sync, by resource type.
+type SyncSummary struct {
+	User          bool
+	Projects      int
+	Sections      int
+	Items         int
+	Notes         int
+	Labels        int
+	Filters       int
+	Reminders     int
+	Collaborators int
+}
+
+// WarmUp fills all caches by a single full sync, and returns the number of the resources loaded,
+// so that callers can check the caches before relying on Resolve.
+// Queued commands are neither sent nor discarded.
+// Resources excluded by WithResourceTypes are counted as 0.
+func (c *Client) WarmUp(ctx context.Context) (*SyncSummary, error) {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	c.resetState()
+	state, err := c.sync(ctx, []Command{})
+	if err != nil {
+		return nil, err
+	}
+	return &SyncSummary{
+		User:          state.User != nil,
+		Projects:      len(state.Projects),
+		Sections:      len(state.Sections),
+		Items:         len(state.Items),
+		Notes:         len(state.Notes) + len(state.ProjectNotes),
+		Labels:        len(state.Labels),
+		Filters:       len(state.Filters),
+		Reminders:     len(state.Reminders),
+		Collaborators: len(state.Collaborators),
+	}, nil
+}
+
+// optimistic reports whether local changes are stored in the caches before the commit.
+func (c *Client) optimistic() bool {
+	return c.cacheMode == CacheOptimistic
+}
+
+// addCommand appends the command to the queue. It is safe for concurrent use.
+// A command adding a resource replaces the queued one with the same temporary id,
+// as caches replace the resource with the same id.
+func (c *Client) addCommand(command Command) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !command.TempID.IsZero() {
+		for i, queued := range c.queue {
+			if queued.TempID == command.TempID && queued.Type == command.Type {
+				c.queue[i] = command
+				return
+			}
+		}
+	}
+	c.queue = append(c.queue, command)
+}
+
+// enqueue queues a command of the type with a new uuid.
+// Give the temporary id of the resource to add, or an empty id for the other commands.
+func (c *Client) enqueue(cmdType string, args interface{}, tempID ID) {
+	c.addCommand(Command{
+		Type:   cmdType,
+		Args:   args,
+		UUID:   GenerateUUID(),
+		TempID: tempID,
+	})
+}
+
+// nextChunk returns a copy of the first commands to be sent in a request.
+func (c *Client) nextChunk() []Command {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := len(c.queue)
+	if n > maxCommands {
+		n = maxCommands
+	}
+	chunk := make([]Command, n)
+	copy(chunk, c.queue)
+	return chunk
+}
+
+// dropCommands removes the first n commands from the queue.
+func (c *Client) dropCommands(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.queue = append([]Command{}, c.queue[n:]...)
+}
+
+// requeueFailed puts the commands of the chunk rejected by the server back to the head of the queue.
+// They get new uuids, since the server does not apply a command with the same uuid again.
+// Day orders rejected for a stale timestamp are not queued again, since they would be rejected again.
+func (c *Client) requeueFailed(chunk []Command, commitErr *CommitError) {
+	rejected := map[UUID]string{}
+	for _, e := range commitErr.Errors {
+		rejected[e.UUID] = e.Tag
+	}
+	var failed, retry []Command
+	for _, command := range chunk {
+		tag, ok := rejected[command.UUID]
+		if !ok {
+			continue
+		}
+		failed = append(failed, command)
+		if tag == dayOrdersConflictTag {
+			continue
+		}
+		command.UUID = GenerateUUID()
+		retry = append(retry, command)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failed = failed
+	c.queue = append(retry, c.queue...)
+}
+
+// FailedCommands returns a copy of the commands rejected by the server in the last Commit,
+// as they were sent. They are queued again with new uuids, so that the next Commit retries them.
+// Use Discard to drop them instead. It returns nil if the last Commit has no rejected command.
+func (c *Client) FailedCommands() []Command {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.failed) == 0 {
 		return nil
 	}
-	err := c.Sync(ctx, c.queue)
+	res := make([]Command, len(c.failed))
+	copy(res, c.failed)
+	return res
+}
+
+// maxCommands is the maximum number of commands the server accepts in a request.
+const maxCommands = 100
+
+// Commit sends queued commands to the server.
+// The queue is split into chunks of maxCommands commands, which are sent in order.
+// Temporary ids resolved by a chunk or by an earlier commit are replaced in the following chunks.
+// When a chunk fails, Commit stops and returns *ChunkError.
+// Chunks processed by the server are removed from the queue, except the commands rejected by the server.
+// In that case, the chunk error wraps *CommitError, and the rejected commands are left at the head
+// of the queue with new uuids, so that the next Commit retries just them, except day orders rejected
+// for a stale timestamp. See FailedCommands.
+// Commands queued concurrently while committing are sent in the same commit.
+// It returns *RestoreError if all commands are committed, but the items of unarchived sections are not fetched.
+func (c *Client) Commit(ctx context.Context) error {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	c.mu.Lock()
+	c.failed = nil
+	// queued commands may refer to temporary ids resolved by the last commit, e.g. after a chunk error.
+	mapping := make(map[ID]ID, len(c.tempIDs))
+	for k, v := range c.tempIDs {
+		mapping[k] = v
+	}
+	c.mu.Unlock()
+	var unarchived []ID
+	for i := 0; ; i++ {
+		queued := c.nextChunk()
+		if len(queued) == 0 {
+			return c.Section.restoreItems(ctx, unarchived)
+		}
+		chunk := replaceTempIDArgs(queued, mapping)
+		state, err := c.sync(ctx, chunk)
+		if state != nil {
+			c.dropCommands(len(chunk))
+			for k, v := range state.TempIDMapping {
+				mapping[k] = v
+			}
+			unarchived = append(unarchived, unarchivedSections(chunk, err)...)
+		}
+		if err != nil {
+			var commitErr *CommitError
+			if state != nil && errors.As(err, &commitErr) {
+				c.requeueFailed(chunk, commitErr)
+			}
+			c.Section.restoreItems(ctx, unarchived)
+			return &ChunkError{Index: i, Commands: chunk, Err: err}
+		}
+	}
+}
+
+// Pending returns a copy of the queued commands.
+func (c *Client) Pending() []Command {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := make([]Command, len(c.queue))
+	copy(res, c.queue)
+	return res
+}
+
+// Discard drops the queued commands, and reverts the caches to the resources confirmed by the server
+// in the last sync. Local changes by Add, Move and so on are dropped as well, even if syncs have
+// happened since they were queued.
+func (c *Client) Discard() {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	c.mu.Lock()
 	c.queue = []Command{}
+	c.failed = nil
+	c.mu.Unlock()
+	c.resetCaches(c.syncState)
+}
+
+// Close commits the queued commands, or discards them with WithDiscardOnClose,
+// and closes idle connections of the HTTP client. It returns the error of the commit.
+// Calling Close again does nothing.
+func (c *Client) Close(ctx context.Context) error {
+	c.mu.Lock()
+	closed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+	if closed {
+		return nil
+	}
+	var err error
+	if c.discardOnClose {
+		c.Discard()
+	} else {
+		err = c.Commit(ctx)
+	}
+	c.HTTPClient.CloseIdleConnections()
 	return err
 }
 
+// settleCommands rolls back local changes of the commands rejected by the server.
+// Only section, item and project commands are rolled back for now.
+func (c *Client) settleCommands(commands []Command, err error) {
+	failed := map[UUID]bool{}
+	var commitErr *CommitError
+	if errors.As(err, &commitErr) {
+		for _, e := range commitErr.Errors {
+			failed[e.UUID] = true
+		}
+	}
+	for _, command := range commands {
+		id := commandTargetID(command)
+		if id.IsZero() {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(command.Type, "section_"):
+			c.Section.cache.settle(id, !failed[command.UUID])
+		case strings.HasPrefix(command.Type, "item_"):
+			c.Item.cache.settle(id, !failed[command.UUID])
+		case strings.HasPrefix(command.Type, "project_"):
+			c.Project.cache.settle(id, !failed[command.UUID])
+		}
+	}
+}
+
+// unarchivedSections returns the ids of the sections unarchived by the commands, except by the rejected ones.
+func unarchivedSections(commands []Command, err error) []ID {
+	failed := map[UUID]bool{}
+	var commitErr *CommitError
+	if errors.As(err, &commitErr) {
+		for _, e := range commitErr.Errors {
+			failed[e.UUID] = true
+		}
+	}
+	var res []ID
+	for _, command := range commands {
+		if command.Type == "section_unarchive" && !failed[command.UUID] {
+			if id := commandTargetID(command); !id.IsZero() {
+				res = append(res, id)
+			}
+		}
+	}
+	return res
+}
+
+// commandTargetID returns the id of the resource the command changes, or an empty id if unknown.
+func commandTargetID(command Command) ID {
+	if !command.TempID.IsZero() {
+		return command.TempID
+	}
+	b, err := json.Marshal(command.Args)
+	if err != nil {
+		return ""
+	}
+	var target struct {
+		ID ID `json:"id"`
+	}
+	if err = json.Unmarshal(b, &target); err != nil {
+		return ""
+	}
+	return target.ID
+}
+
+// DryRun returns the parameters Commit would send, one per request, without sending them.
+// The queue is kept as is. The API token is not included.
+// Temporary ids are not replaced in the second and later requests,
+// since they are resolved by the server.
+func (c *Client) DryRun() ([]url.Values, error) {
+	c.mu.Lock()
+	queue := make([]Command, len(c.queue))
+	copy(queue, c.queue)
+	c.mu.Unlock()
+	var res []url.Values
+	for len(queue) > 0 {
+		n := len(queue)
+		if n > maxCommands {
+			n = maxCommands
+		}
+		values, err := c.syncValues(queue[:n])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, values)
+		queue = queue[n:]
+	}
+	return res, nil
+}
+
+// replaceTempIDArgs returns commands whose args refer to real ids instead of resolved temporary ids.
+func replaceTempIDArgs(commands []Command, mapping map[ID]ID) []Command {
+	if len(mapping) == 0 {
+		return commands
+	}
+	res := make([]Command, len(commands))
+	for i, command := range commands {
+		res[i] = command
+		b, err := json.Marshal(command.Args)
+		if err != nil {
+			continue
+		}
+		s := string(b)
+		for tempID, id := range mapping {
+			v, _ := id.MarshalJSON()
+			s = strings.Replace(s, strconv.Quote(tempID.String()), string(v), -1)
+		}
+		res[i].Args = json.RawMessage(s)
+	}
+	return res
+}
+
+// SyncToken returns the token to be sent with the next sync.
+// Store it to resume incremental syncs on a new client.
+func (c *Client) SyncToken() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.syncToken) == 0 {
+		return "*"
+	}
+	return c.syncToken
+}
+
+// SetSyncToken sets the token to be sent with the next sync.
+// An empty token or "*" requests a full sync.
+func (c *Client) SetSyncToken(token string) {
+	if len(token) == 0 {
+		token = "*"
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.syncToken = token
+}
+
+// DayOrdersTimestamp returns the version of the day orders by the last sync, which ReorderDay sends
+// so that the server rejects the orders changed concurrently. It returns "" before a sync.
+func (c *Client) DayOrdersTimestamp() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.dayOrdersTimestamp
+}
+
+func (c *Client) setDayOrdersTimestamp(timestamp string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dayOrdersTimestamp = timestamp
+}
+
 func (c *Client) ResetSyncToken() {
-	c.SyncToken = "*"
+	c.SetSyncToken("*")
 }
 
 func (c *Client) resetState() {
-	c.SyncToken = "*"
-	c.syncState = &SyncState{}
+	c.SetSyncToken("*")
+	c.setDayOrdersTimestamp("")
+}
+
+func (c *Client) storeTempIDs(mapping map[ID]ID) {
+	if len(mapping) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tempIDs == nil {
+		c.tempIDs = map[ID]ID{}
+	}
+	for k, v := range mapping {
+		c.tempIDs[k] = v
+	}
+}
+
+// queuedTempID reports whether the temporary id is of a resource added by a queued command.
+func (c *Client) queuedTempID(id ID) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, command := range c.queue {
+		if command.TempID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveTempID returns the id assigned by the server for the temporary id generated by GenerateTempID.
+// It returns the given id as is if it is not resolved by Sync or Commit yet, or it is not a temporary id.
+func (c *Client) ResolveTempID(id ID) ID {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if resolved, ok := c.tempIDs[id]; ok {
+		return resolved
+	}
+	return id
+}
+
+// replaceTempIDs replaces temporary ids of cached resources with the ids assigned by the server,
+// including references to other resources, e.g. the project id of sections added in a new project.
+func (c *Client) replaceTempIDs(mapping map[ID]ID) {
+	if len(mapping) == 0 {
+		return
+	}
+	c.Filter.cache.replaceTempIDs(mapping)
+	c.Item.cache.replaceTempIDs(mapping)
+	c.Label.cache.replaceTempIDs(mapping)
+	c.Project.cache.replaceTempIDs(mapping)
+	c.Section.cache.replaceTempIDs(mapping)
+	c.Note.cache.replaceTempIDs(mapping)
+	c.Reminder.cache.replaceTempIDs(mapping)
+}
+
+// mapTempID returns the id mapped from the temporary id, or the id as is.
+func mapTempID(id ID, mapping map[ID]ID) ID {
+	if mapped, ok := mapping[id]; ok {
+		return mapped
+	}
+	return id
 }
 
 func (c *Client) updateState(state *SyncState) {
 	if len(state.SyncToken) != 0 {
-		c.SyncToken = state.SyncToken
+		c.SetSyncToken(state.SyncToken)
+	}
+	if len(state.DayOrdersTimestamp) != 0 {
+		c.setDayOrdersTimestamp(state.DayOrdersTimestamp)
 	}
 	/* TODO:
 	- day_orders
-	- day_orders_timestamp
 	- live_notifications_last_read_id
 	- locations
 	- settings_notifications
-	- user
 	*/
-	for _, filter := range state.Filters {
-		c.Filter.cache.store(filter)
-	}
-	for _, item := range state.Items {
-		c.Item.cache.store(item)
-	}
-	for _, label := range state.Labels {
-		c.Label.cache.store(label)
+	c.applyState(c.caches(), state)
+	// the confirmed state is merged apart from the caches, so that it has no local changes.
+	confirmed := newCaches(c.syncState, nil)
+	c.applyState(confirmed, state)
+	c.syncState = confirmed.state()
+	c.syncState.SyncToken = c.SyncToken()
+	c.syncState.DayOrdersTimestamp = c.DayOrdersTimestamp()
+}
+
+// applyState merges the sync state into the caches.
+func (c *Client) applyState(cs *caches, state *SyncState) {
+	if state.User != nil {
+		cs.user.store(*state.User)
+	}
+	if state.FullSync {
+		// full sync returns all resources, so drop stale ones.
+		// resources not requested by WithResourceTypes are kept as is.
+		if c.syncsResource("filters") {
+			cs.filter.replace(state.Filters)
+		}
+		if c.syncsResource("items") {
+			cs.item.replace(state.Items)
+		}
+		if c.syncsResource("labels") {
+			cs.label.replace(state.Labels)
+		}
+		if c.syncsResource("projects") {
+			cs.project.replace(state.Projects)
+		}
+		if c.syncsResource("sections") {
+			cs.section.replace(state.Sections)
+		}
+		if c.syncsResource("notes") || c.syncsResource("project_notes") {
+			cs.note.replace(append(state.Notes, state.ProjectNotes...))
+		}
+		if c.syncsResource("reminders") {
+			cs.reminder.replace(state.Reminders)
+		}
+		if c.syncsResource("collaborators") {
+			cs.collaborator.replace(state.Collaborators, state.CollaboratorStates)
+		}
+	} else {
+		for _, filter := range state.Filters {
+			cs.filter.store(filter)
+		}
+		for _, item := range state.Items {
+			cs.item.store(item)
+		}
+		for _, label := range state.Labels {
+			cs.label.store(label)
+		}
+		for _, project := range state.Projects {
+			cs.project.store(project)
+		}
+		for _, section := range state.Sections {
+			cs.section.store(section)
+		}
+		for _, note := range state.Notes {
+			cs.note.store(note)
+		}
+		for _, note := range state.ProjectNotes {
+			cs.note.store(note)
+		}
+		for _, reminder := range state.Reminders {
+			cs.reminder.store(reminder)
+		}
+		for _, collaborator := range state.Collaborators {
+			cs.collaborator.store(collaborator)
+		}
+		for _, collaboratorState := range state.CollaboratorStates {
+			cs.collaborator.storeState(collaboratorState)
+		}
 	}
-	for _, project := range state.Projects {
-		c.Project.cache.store(project)
+}
+
+// cachedState returns the resources in the caches, including local changes.
+func (c *Client) cachedState() *SyncState {
+	state := c.caches().state()
+	state.SyncToken = c.SyncToken()
+	state.DayOrdersTimestamp = c.DayOrdersTimestamp()
+	return state
+}
+
+// caches is the set of the caches of all resources, which sync states are merged into.
+type caches struct {
+	filter       *filterCache
+	item         *itemCache
+	label        *labelCache
+	project      *projectCache
+	section      *sectionCache
+	note         *noteCache
+	reminder     *reminderCache
+	collaborator *collaboratorCache
+	user         *userCache
+}
+
+// newCaches returns caches of copies of the resources in the state.
+func newCaches(state *SyncState, notify *notifier) *caches {
+	filters := append([]Filter{}, state.Filters...)
+	items := append([]Item{}, state.Items...)
+	labels := append([]Label{}, state.Labels...)
+	projects := append([]Project{}, state.Projects...)
+	sections := append([]Section{}, state.Sections...)
+	notes := append([]Note{}, state.Notes...)
+	reminders := append([]Reminder{}, state.Reminders...)
+	collaborators := append([]Collaborator{}, state.Collaborators...)
+	collaboratorStates := append([]CollaboratorState{}, state.CollaboratorStates...)
+	var user *User
+	if state.User != nil {
+		u := *state.User
+		user = &u
+	}
+	return &caches{
+		filter:       &filterCache{cache: &filters, index: indexFilters(filters), notify: notify},
+		item:         &itemCache{cache: &items, index: indexItems(items), notify: notify},
+		label:        &labelCache{cache: &labels, index: indexLabels(labels), notify: notify},
+		project:      &projectCache{cache: &projects, index: indexProjects(projects), notify: notify},
+		section:      &sectionCache{cache: &sections, index: indexSections(sections), notify: notify},
+		note:         &noteCache{cache: &notes, index: indexNotes(notes), notify: notify},
+		reminder:     &reminderCache{cache: &reminders, index: indexReminders(reminders), notify: notify},
+		collaborator: &collaboratorCache{cache: &collaborators, index: indexCollaborators(collaborators), states: &collaboratorStates, notify: notify},
+		user:         &userCache{user: user, notify: notify},
 	}
-	for _, note := range state.Notes {
-		c.Note.cache.store(note)
+}
+
+// caches returns the caches of the clients.
+func (c *Client) caches() *caches {
+	return &caches{
+		filter:       c.Filter.cache,
+		item:         c.Item.cache,
+		label:        c.Label.cache,
+		project:      c.Project.cache,
+		section:      c.Section.cache,
+		note:         c.Note.cache,
+		reminder:     c.Reminder.cache,
+		collaborator: c.Collaborator.cache,
+		user:         c.user,
 	}
-	for _, note := range state.ProjectNotes {
-		c.Note.cache.store(note)
+}
+
+// state returns copies of the cached resources.
+func (cs *caches) state() *SyncState {
+	return &SyncState{
+		User:               cs.user.get(),
+		Projects:           cs.project.getAll(),
+		Sections:           cs.section.getAll(),
+		Items:              cs.item.getAll(),
+		Notes:              cs.note.getAll(),
+		Labels:             cs.label.getAll(),
+		Filters:            cs.filter.getAll(),
+		Reminders:          cs.reminder.getAll(),
+		Collaborators:      cs.collaborator.getAll(),
+		CollaboratorStates: cs.collaborator.getAllStates(),
 	}
-	c.syncState = state
+}
+
+// resetCaches sets all caches to the resources in the state, dropping local changes.
+func (c *Client) resetCaches(state *SyncState) {
+	c.Filter.cache.reset(state.Filters)
+	c.Item.cache.reset(state.Items)
+	c.Label.cache.reset(state.Labels)
+	c.Project.cache.reset(state.Projects)
+	c.Section.cache.reset(state.Sections)
+	c.Note.cache.reset(state.Notes)
+	c.Reminder.cache.reset(state.Reminders)
+	c.Collaborator.cache.replace(state.Collaborators, state.CollaboratorStates)
+	c.user.reset(state.User)
+}
+
+// cachePath returns the path of the cache file with the extension. Clients limited by WithResourceTypes
+// have their own files, since their sync tokens do not cover the other resources.
+func (c *Client) cachePath(ext string) string {
+	name := c.Token
+	if !c.syncsResource("all") {
+		resourceTypes := append([]string{}, c.resourceTypes...)
+		sort.Strings(resourceTypes)
+		name += "." + strings.Join(resourceTypes, "-")
+	}
+	return path.Join(c.CacheDir, name+ext)
 }
 
 func (c *Client) readCache() error {
-	b, err := ioutil.ReadFile(path.Join(c.CacheDir, c.Token+".json"))
+	b, err := ioutil.ReadFile(c.cachePath(".json"))
 	if err != nil {
 		return err
 	}
 	if err = json.Unmarshal(b, c.syncState); err != nil {
 		return err
 	}
-	b, err = ioutil.ReadFile(path.Join(c.CacheDir, c.Token+".sync"))
+	b, err = ioutil.ReadFile(c.cachePath(".sync"))
 	if err != nil {
 		return err
 	}
-	c.SyncToken = string(b)
+	c.syncToken = string(b)
+	c.dayOrdersTimestamp = c.syncState.DayOrdersTimestamp
 	return nil
 }
 
@@ -239,10 +1008,10 @@ func (c *Client) writeCache() error {
 	if err != nil {
 		return err
 	}
-	if err = ioutil.WriteFile(path.Join(c.CacheDir, c.Token+".json"), b, 0644); err != nil {
+	if err = ioutil.WriteFile(c.cachePath(".json"), b, 0644); err != nil {
 		return err
 	}
-	if err = ioutil.WriteFile(path.Join(c.CacheDir, c.Token+".sync"), []byte(c.SyncToken), 0644); err != nil {
+	if err = ioutil.WriteFile(c.cachePath(".sync"), []byte(c.SyncToken()), 0644); err != nil {
 		return err
 	}
 	return nil
@@ -0,0 +1,670 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*Client, func()) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	client, err := NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir), WithRetryPolicy(nil))
+	if err != nil {
+		server.Close()
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	return client, func() {
+		server.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestClient_Commit(t *testing.T) {
+	var commands []Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token": "next-token",
+			"sync_status": map[UUID]interface{}{
+				commands[0].UUID: "ok",
+				commands[1].UUID: map[string]interface{}{"error_code": 15, "error": "Invalid temporary id"},
+			},
+			"temp_id_mapping": map[ID]int{
+				commands[0].TempID: 1000,
+			},
+		})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	project, _ := NewProject("test", &NewProjectOpts{})
+	client.Project.Add(*project)
+	client.Project.Delete(GenerateTempID())
+
+	err := client.Commit(context.Background())
+	var commitErr *CommitError
+	if !errors.As(err, &commitErr) {
+		t.Fatalf("Expect *CommitError, but got %v", err)
+	}
+	if len(commitErr.Errors) != 1 || commitErr.Errors[0].UUID != commands[1].UUID || commitErr.Errors[0].Code != 15 {
+		t.Errorf("Unexpect command errors: %v", commitErr.Errors)
+	}
+	if len(client.queue) != 1 || client.queue[0].Type != "project_delete" {
+		t.Errorf("Expect the failed command to be left, but got %v", client.queue)
+	}
+	if client.Project.Resolve(project.ID) != nil {
+		t.Errorf("Expect temp id %s to be replaced", project.ID)
+	}
+	if p := client.Project.Resolve("1000"); p == nil || p.Name != "test" {
+		t.Errorf("Expect project with id 1000, but got %v", p)
+	}
+}
+
+func TestClient_SyncToken(t *testing.T) {
+	var tokens []string
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		token := r.FormValue("sync_token")
+		tokens = append(tokens, token)
+		if token == "*" {
+			w.Write([]byte(`{"sync_token": "token-1", "full_sync": true, "projects": [{"id": 1, "name": "a"}]}`))
+		} else {
+			w.Write([]byte(`{"sync_token": "token-2", "full_sync": false, "projects": [{"id": 2, "name": "b"}]}`))
+		}
+	})
+	defer teardown()
+
+	if client.SyncToken() != "*" {
+		t.Errorf("Expect %s, but got %s", "*", client.SyncToken())
+	}
+	ctx := context.Background()
+	if err := client.Sync(ctx, []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Sync(ctx, []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if client.SyncToken() != "token-2" {
+		t.Errorf("Expect %s, but got %s", "token-2", client.SyncToken())
+	}
+	if len(client.Project.GetAll()) != 2 {
+		t.Errorf("Expect delta to be merged, but got %v", client.Project.GetAll())
+	}
+
+	client.SetSyncToken("")
+	if err := client.Sync(ctx, []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(client.Project.GetAll()) != 1 {
+		t.Errorf("Expect full sync to reload projects, but got %v", client.Project.GetAll())
+	}
+	expect := []string{"*", "token-1", "*"}
+	if !reflect.DeepEqual(tokens, expect) {
+		t.Errorf("Expect %v, but got %v", expect, tokens)
+	}
+}
+
+func TestClient_CommitChunks(t *testing.T) {
+	var sizes []int
+	var tokens []string
+	var lastArgs json.RawMessage
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []struct {
+			Type   string          `json:"type"`
+			Args   json.RawMessage `json:"args"`
+			TempID ID              `json:"temp_id"`
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		sizes = append(sizes, len(commands))
+		tokens = append(tokens, r.FormValue("sync_token"))
+		lastArgs = commands[len(commands)-1].Args
+		res := map[string]interface{}{"sync_token": "token-" + strconv.Itoa(len(sizes))}
+		if len(sizes) == 1 {
+			res["temp_id_mapping"] = map[ID]int{commands[0].TempID: 1000}
+		}
+		json.NewEncoder(w).Encode(res)
+	})
+	defer teardown()
+	client.SetSyncToken("token-0")
+
+	project, _ := NewProject("test", &NewProjectOpts{})
+	client.Project.Add(*project)
+	for i := 0; i < 248; i++ {
+		client.Project.Archive("1")
+	}
+	client.Project.Archive(project.ID)
+
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := []int{100, 100, 50}; !reflect.DeepEqual(sizes, expect) {
+		t.Errorf("Expect %v, but got %v", expect, sizes)
+	}
+	if expect := []string{"token-0", "token-1", "token-2"}; !reflect.DeepEqual(tokens, expect) {
+		t.Errorf("Expect %v, but got %v", expect, tokens)
+	}
+	if expect := `{"id":"1000"}`; string(lastArgs) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(lastArgs))
+	}
+	if len(client.queue) != 0 {
+		t.Errorf("Expect empty queue, but got %d command(s)", len(client.queue))
+	}
+}
+
+func TestClient_CommitChunkError(t *testing.T) {
+	requests := 0
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"sync_token": "token"}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	for i := 0; i < 250; i++ {
+		client.Project.Archive("1")
+	}
+	err := client.Commit(context.Background())
+	chunkErr, ok := err.(*ChunkError)
+	if !ok {
+		t.Fatalf("Expect *ChunkError, but got %v", err)
+	}
+	if chunkErr.Index != 1 || len(chunkErr.Commands) != 100 {
+		t.Errorf("Expect chunk 1 with 100 commands, but got chunk %d with %d commands", chunkErr.Index, len(chunkErr.Commands))
+	}
+	if len(client.queue) != 150 {
+		t.Errorf("Expect 150 commands left, but got %d", len(client.queue))
+	}
+}
+
+func TestClient_CommitAfterChunkError(t *testing.T) {
+	var sent [][]Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		sent = append(sent, commands)
+		if len(sent) == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		mapping := map[ID]int{}
+		if len(sent) == 1 {
+			mapping[commands[0].TempID] = 100
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_token": "next-token", "temp_id_mapping": mapping})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	project, _ := NewProject("project", nil)
+	client.Project.Add(*project)
+	for i := 0; i < 99; i++ {
+		client.Project.Archive("1")
+	}
+	client.Project.Archive(project.ID)
+	if err := client.Commit(context.Background()); err == nil {
+		t.Fatal("Expect error, but got nil")
+	}
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(sent) != 3 || len(sent[2]) != 1 || sent[2][0].Args.(map[string]interface{})["id"] != "100" {
+		t.Errorf("Expect the temporary id resolved by the last commit to be replaced, but got %v", sent[2])
+	}
+}
+
+func TestClient_Concurrent(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "token"}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				item, _ := NewItem("test", &NewItemOpts{})
+				client.Item.Add(*item)
+				client.Item.GetAll()
+			}
+		}()
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := client.Commit(context.Background()); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+	}()
+	wg.Wait()
+	if err := client.Commit(context.Background()); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if len(client.queue) != 0 {
+		t.Errorf("Expect empty queue, but got %d command(s)", len(client.queue))
+	}
+	if n := len(client.Item.GetAll()); n != 200 {
+		t.Errorf("Expect %d items, but got %d", 200, n)
+	}
+}
+
+func TestClient_ReloadAll(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if commands := r.FormValue("commands"); commands != "[]" {
+			t.Errorf("Expect no command to be sent, but got %s", commands)
+		}
+		w.Write([]byte(`{"sync_token": "token", "full_sync": true, "projects": [{"id": 1, "name": "fresh"}]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	stale := Project{Name: "stale"}
+	stale.ID = "2"
+	client.Project.cache.store(stale)
+	project, _ := NewProject("queued", &NewProjectOpts{})
+	client.Project.Add(*project)
+
+	if err := client.ReloadAll(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(client.queue) != 1 {
+		t.Errorf("Expect queued command to be kept, but got %d command(s)", len(client.queue))
+	}
+	if client.Project.Resolve("2") != nil {
+		t.Error("Expect stale project to be dropped")
+	}
+	if client.Project.Resolve("1") == nil || client.Project.Resolve(project.ID) == nil {
+		t.Errorf("Expect fresh and queued projects, but got %v", client.Project.GetAll())
+	}
+}
+
+func TestClient_ResolveTempID(t *testing.T) {
+	var commands []Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		mapping := map[ID]int{}
+		for i, command := range commands {
+			mapping[command.TempID] = 1000 + i
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token":      "next-token",
+			"temp_id_mapping": mapping,
+		})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	project, _ := NewProject("project", &NewProjectOpts{})
+	client.Project.Add(*project)
+	section, _ := NewSection("section", &NewSectionOpts{ProjectID: project.ID})
+	client.Section.Add(*section)
+	item, _ := NewItem("item", &NewItemOpts{ProjectID: project.ID, SectionID: section.ID})
+	client.Item.Add(*item)
+
+	if id := client.ResolveTempID(project.ID); id != project.ID {
+		t.Errorf("Expect %s before commit, but got %s", project.ID, id)
+	}
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	for i, id := range []ID{project.ID, section.ID, item.ID} {
+		if resolved := client.ResolveTempID(id); resolved != ID(strconv.Itoa(1000+i)) {
+			t.Errorf("Expect %d, but got %s", 1000+i, resolved)
+		}
+	}
+	if id := client.ResolveTempID("1"); id != "1" {
+		t.Errorf("Expect %s, but got %s", "1", id)
+	}
+}
+
+func TestClient_Discard(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token", "items": [{"id": 1, "content": "item", "project_id": 10}]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+
+	section, _ := NewSection("section", &NewSectionOpts{ProjectID: "10"})
+	client.Section.Add(*section)
+	client.Item.Move("1", &ItemMoveOpts{ProjectID: "20"})
+	pending := client.Pending()
+	if len(pending) != 2 || pending[0].Type != "section_add" {
+		t.Fatalf("Unexpect pending commands: %v", pending)
+	}
+	pending[0].Type = "modified"
+	if client.queue[0].Type != "section_add" {
+		t.Error("Expect Pending to return a copy")
+	}
+
+	client.Discard()
+	if len(client.Pending()) != 0 {
+		t.Errorf("Expect empty queue, but got %d command(s)", len(client.Pending()))
+	}
+	if sections := client.Section.GetAll(); len(sections) != 0 {
+		t.Errorf("Expect added section to be dropped, but got %v", sections)
+	}
+	if item := client.Item.Resolve("1"); item == nil || item.ProjectID != "10" {
+		t.Errorf("Expect moved item to be reverted, but got %v", item)
+	}
+}
+
+func TestClient_DiscardAfterSync(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token", "items": [{"id": 1, "content": "item", "project_id": 10}], "collaborators": [{"id": 5, "full_name": "user"}]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+
+	client.Item.UpdateFields("1", map[string]interface{}{"content": "updated"})
+	added, _ := NewItem("added", nil)
+	client.Item.Add(*added)
+	client.Collaborator.cache.store(Collaborator{Entity: Entity{ID: "6"}, FullName: "local"})
+	// the server returns the item as is, since the commands are not sent yet.
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if item := client.Item.Resolve("1"); item == nil || item.Content != "updated" {
+		t.Errorf("Expect local change to be kept, but got %v", item)
+	}
+	b, err := ioutil.ReadFile(path.Join(client.CacheDir, client.Token+".json"))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if s := string(b); strings.Contains(s, `"updated"`) || strings.Contains(s, `"added"`) {
+		t.Errorf("Expect cache file without local changes, but got %s", s)
+	}
+
+	client.Discard()
+	if item := client.Item.Resolve("1"); item == nil || item.Content != "item" {
+		t.Errorf("Expect updated item to be reverted, but got %v", item)
+	}
+	if item := client.Item.Resolve(added.ID); item != nil {
+		t.Errorf("Expect added item to be dropped, but got %v", item)
+	}
+	if collaborators := client.Collaborator.GetAll(); len(collaborators) != 1 || collaborators[0].ID != "5" {
+		t.Errorf("Expect synced collaborators, but got %v", collaborators)
+	}
+}
+
+func TestClient_SyncCancel(t *testing.T) {
+	done := make(chan struct{})
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	})
+	defer teardown()
+	defer close(done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	start := time.Now()
+	err := client.Sync(ctx, []Command{})
+	if err != context.Canceled {
+		t.Errorf("Expect %s, but got %v", context.Canceled, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expect to return promptly, but took %s", elapsed)
+	}
+}
+
+func TestClient_Close(t *testing.T) {
+	var requests int
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"sync_token": "next-token"}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	client.Project.Archive("1")
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.Project.Archive("2")
+	if err := client.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if requests != 1 || len(client.Pending()) != 1 {
+		t.Errorf("Expect only first close to commit, but got %d request(s)", requests)
+	}
+
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	client, err = NewClient("test-token", WithBaseURL(client.URL.String()), WithCacheDir(dir), WithDiscardOnClose())
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.Project.Archive("1")
+	if err = client.Close(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if requests != 1 || len(client.Pending()) != 0 {
+		t.Errorf("Expect queue to be discarded, but got %d request(s)", requests)
+	}
+}
+
+func TestClient_CommitReplacesTempIDReferences(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		mapping := map[ID]int{}
+		for i, command := range commands {
+			mapping[command.TempID] = 100 + i
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_token": "next-token", "temp_id_mapping": mapping})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	project, _ := NewProject("project", &NewProjectOpts{})
+	client.Project.Add(*project)
+	section, _ := NewSection("section", &NewSectionOpts{ProjectID: project.ID})
+	client.Section.Add(*section)
+	item, _ := NewItem("item", &NewItemOpts{ProjectID: project.ID, SectionID: section.ID})
+	client.Item.Add(*item)
+	reminder, _ := NewRelativeReminder(item.ID, 30, &NewReminderOpts{})
+	client.Reminder.Add(*reminder)
+
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if s := client.Section.Resolve("101"); s == nil || s.ProjectID != "100" {
+		t.Errorf("Expect section in project %s, but got %v", "100", s)
+	}
+	if i := client.Item.Resolve("102"); i == nil || i.ProjectID != "100" || i.SectionID != "101" {
+		t.Errorf("Expect item in project %s and section %s, but got %v", "100", "101", i)
+	}
+	if r := client.Reminder.Resolve("103"); r == nil || r.ItemID != "102" {
+		t.Errorf("Expect reminder of item %s, but got %v", "102", r)
+	}
+}
+
+func TestClient_CommitRetriesFailedCommands(t *testing.T) {
+	var sent [][]Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		sent = append(sent, commands)
+		status := map[UUID]interface{}{}
+		for _, command := range commands {
+			status[command.UUID] = "ok"
+		}
+		if len(sent) == 1 {
+			status[commands[1].UUID] = map[string]interface{}{"error_code": 35, "error_tag": "LIMITS_REACHED", "error": "Limits reached"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_token": "next-token", "sync_status": status})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	client.Project.Archive("1")
+	client.Project.Archive("2")
+	client.Project.Archive("3")
+	if err := client.Commit(context.Background()); !errors.Is(err, ErrLimitsReached) {
+		t.Fatalf("Expect %s, but got %v", ErrLimitsReached, err)
+	}
+	failed := client.FailedCommands()
+	if len(failed) != 1 || failed[0].UUID != sent[0][1].UUID {
+		t.Fatalf("Unexpect failed commands: %v", failed)
+	}
+	pending := client.Pending()
+	if len(pending) != 1 || pending[0].UUID == failed[0].UUID || !reflect.DeepEqual(pending[0].Args, failed[0].Args) {
+		t.Fatalf("Expect only the failed command to be queued, but got %v", pending)
+	}
+
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(sent) != 2 || len(sent[1]) != 1 || sent[1][0].Args.(map[string]interface{})["id"] != "2" {
+		t.Errorf("Expect only the failed command to be retried, but got %v", sent)
+	}
+	if len(client.Pending()) != 0 || client.FailedCommands() != nil {
+		t.Errorf("Expect nothing left, but got %v, %v", client.Pending(), client.FailedCommands())
+	}
+}
+
+func TestClient_SyncV9(t *testing.T) {
+	var commands []Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		if len(commands) == 0 {
+			w.Write([]byte(`{
+				"sync_token": "next-token",
+				"projects": [{"id": "2203306141", "name": "Inbox", "collapsed": false, "is_favorite": true}],
+				"sections": [{"id": "7025", "name": "Todo", "project_id": "2203306141", "added_at": "2020-01-02T03:04:05Z", "archived_at": null}],
+				"items": [
+					{"id": "2995104339", "content": "item", "project_id": "2203306141", "section_id": "7025", "parent_id": null,
+					 "checked": true, "is_deleted": false, "added_at": "2020-01-02T03:04:05Z", "completed_at": "2020-01-03T03:04:05Z"}
+				],
+				"notes": [{"id": "2992679862", "item_id": "2995104339", "content": "note", "posted_at": "2020-01-02T03:04:05Z"}],
+				"reminders": [{"id": "2992683215", "item_id": "2995104339", "type": "relative", "minute_offset": 30, "is_deleted": false}]
+			}`))
+			return
+		}
+		mapping := map[ID]string{}
+		for _, command := range commands {
+			if !command.TempID.IsZero() {
+				mapping[command.TempID] = "2995104340"
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_token": "next-token", "temp_id_mapping": mapping})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	item := client.Item.Resolve("2995104339")
+	if item == nil || !item.Checked.Bool() || !item.ParentID.IsZero() || item.DateAdded.IsZero() || item.CompletedDate.IsZero() {
+		t.Fatalf("Unexpect item: %v", item)
+	}
+	if project := client.Project.Resolve("2203306141"); project == nil || !project.IsFavorite.Bool() {
+		t.Errorf("Unexpect project: %v", project)
+	}
+	if section := client.Section.Resolve("7025"); section == nil || section.DateAdded.IsZero() {
+		t.Errorf("Unexpect section: %v", section)
+	}
+	if note := client.Note.Resolve("2992679862"); note == nil || note.Posted.IsZero() {
+		t.Errorf("Unexpect note: %v", note)
+	}
+	if reminder := client.Reminder.Resolve("2992683215"); reminder == nil || reminder.MmOffset != 30 {
+		t.Errorf("Unexpect reminder: %v", reminder)
+	}
+
+	added, _ := NewItem("added", &NewItemOpts{ProjectID: "2203306141", Collapsed: true})
+	client.Item.Add(*added)
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	args := commands[0].Args.(map[string]interface{})
+	if args["project_id"] != "2203306141" || args["collapsed"] != true {
+		t.Errorf("Expect v9 args, but got %v", args)
+	}
+	if client.Item.Resolve("2995104340") == nil {
+		t.Error("Expect the string id of temp_id_mapping to be resolved")
+	}
+}
+
+func TestClient_WarmUp(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if token := r.FormValue("sync_token"); token != "*" {
+			t.Errorf("Expect %s, but got %s", "*", token)
+		}
+		w.Write([]byte(`{
+			"sync_token": "next-token",
+			"full_sync": true,
+			"user": {"id": 1, "full_name": "user"},
+			"projects": [{"id": 10, "name": "Inbox"}, {"id": 11, "name": "Work"}],
+			"sections": [{"id": 20, "name": "Todo", "project_id": 10}],
+			"items": [{"id": 30, "content": "item", "project_id": 10}],
+			"notes": [{"id": 40, "item_id": 30, "content": "note"}],
+			"project_notes": [{"id": 41, "project_id": 10, "content": "note"}],
+			"labels": [{"id": 50, "name": "work"}],
+			"filters": [{"id": 60, "name": "today", "query": "today"}],
+			"reminders": [{"id": 70, "item_id": 30, "type": "relative", "minute_offset": 30}]
+		}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	summary, err := client.WarmUp(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	expect := SyncSummary{User: true, Projects: 2, Sections: 1, Items: 1, Notes: 2, Labels: 1, Filters: 1, Reminders: 1}
+	if *summary != expect {
+		t.Errorf("Expect %v, but got %v", expect, *summary)
+	}
+	if client.Project.Resolve("11") == nil || client.Section.Resolve("20") == nil || client.Item.Resolve("30") == nil ||
+		client.Note.Resolve("41") == nil || client.Label.Resolve("50") == nil || client.Filter.Resolve("60") == nil ||
+		client.Reminder.Resolve("70") == nil || client.User() == nil {
+		t.Error("Expect all resources to be cached")
+	}
+}
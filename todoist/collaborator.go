@@ -0,0 +1,293 @@
+package todoist
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	CollaboratorStateActive  = "active"
+	CollaboratorStateInvited = "invited"
+	CollaboratorStateDeleted = "deleted"
+)
+
+// Collaborator is a user who shares a project with the user.
+type Collaborator struct {
+	Entity
+	Email    string `json:"email"`
+	FullName string `json:"full_name"`
+	Timezone string `json:"timezone,omitempty"`
+	ImageID  string `json:"image_id,omitempty"`
+}
+
+func (c Collaborator) String() string {
+	return c.FullName
+}
+
+// CollaboratorState is the membership of a collaborator in a shared project.
+type CollaboratorState struct {
+	ProjectID ID      `json:"project_id"`
+	UserID    ID      `json:"user_id"`
+	State     string  `json:"state"`
+	IsDeleted IntBool `json:"is_deleted,omitempty"`
+}
+
+// CollaboratorClient encapsulate client operations for collaborators.
+type CollaboratorClient struct {
+	*Client
+	cache *collaboratorCache
+}
+
+func (c *CollaboratorClient) GetAll() []Collaborator {
+	return c.cache.getAll()
+}
+
+// Resolve returns a copy of the cached collaborator.
+func (c *CollaboratorClient) Resolve(id ID) *Collaborator {
+	return c.cache.resolve(id)
+}
+
+// GetStates returns the cached memberships of the project.
+func (c *CollaboratorClient) GetStates(projectID ID) []CollaboratorState {
+	var res []CollaboratorState
+	for _, state := range c.cache.getAllStates() {
+		if state.ProjectID == projectID {
+			res = append(res, state)
+		}
+	}
+	return res
+}
+
+// Collaborators returns the cached collaborators who are active or invited in the project.
+func (c ProjectClient) Collaborators(projectID ID) []Collaborator {
+	var res []Collaborator
+	for _, state := range c.Collaborator.GetStates(projectID) {
+		if state.State == CollaboratorStateDeleted {
+			continue
+		}
+		if collaborator := c.Collaborator.Resolve(state.UserID); collaborator != nil {
+			res = append(res, *collaborator)
+		}
+	}
+	return res
+}
+
+// Share invites the user of the email to the project.
+func (c *ProjectClient) Share(projectID ID, email string) error {
+	if len(email) == 0 {
+		return fmt.Errorf("share project requires an email")
+	}
+	c.enqueue("share_project", map[string]interface{}{
+		"project_id": projectID,
+		"email":      email,
+	}, "")
+	return nil
+}
+
+// DeleteCollaborator removes the collaborator from the project.
+// The collaborator must be cached, since the server identifies it by the email.
+func (c *ProjectClient) DeleteCollaborator(projectID, userID ID) error {
+	collaborator := c.Collaborator.Resolve(userID)
+	if collaborator == nil {
+		return fmt.Errorf("no such collaborator: %s", userID)
+	}
+	c.enqueue("delete_collaborator", map[string]interface{}{
+		"project_id": projectID,
+		"email":      collaborator.Email,
+	}, "")
+	return nil
+}
+
+type collaboratorCache struct {
+	cache *[]Collaborator
+	// index maps ids to positions in cache.
+	index  map[ID]int
+	states *[]CollaboratorState
+	mu     sync.RWMutex
+	notify *notifier
+}
+
+func (c *collaboratorCache) getAll() []Collaborator {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Collaborator, len(*c.cache))
+	copy(res, *c.cache)
+	return res
+}
+
+func (c *collaboratorCache) getAllStates() []CollaboratorState {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]CollaboratorState, len(*c.states))
+	copy(res, *c.states)
+	return res
+}
+
+func (c *collaboratorCache) resolve(id ID) *Collaborator {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	i, ok := c.lookup(id)
+	if !ok {
+		return nil
+	}
+	res := (*c.cache)[i]
+	return &res
+}
+
+// lookup returns the position of the collaborator in the cache. The caller must hold the lock.
+// The cache is scanned if it is not indexed yet.
+func (c *collaboratorCache) lookup(id ID) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[id]
+		return i, ok
+	}
+	for i, collaborator := range *c.cache {
+		if collaborator.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// set swaps the cache for the collaborators and indexes them. The caller must hold the lock.
+func (c *collaboratorCache) set(collaborators []Collaborator) {
+	c.cache = &collaborators
+	c.index = indexCollaborators(collaborators)
+}
+
+func indexCollaborators(collaborators []Collaborator) map[ID]int {
+	res := make(map[ID]int, len(collaborators))
+	for i, collaborator := range collaborators {
+		res[collaborator.ID] = i
+	}
+	return res
+}
+
+func (c *collaboratorCache) store(collaborator Collaborator) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("collaborators")
+	i, ok := c.lookup(collaborator.ID)
+	switch {
+	case collaborator.IsDeleted.Bool():
+		if ok {
+			c.removeAt(i)
+		}
+	case ok:
+		(*c.cache)[i] = collaborator
+	default:
+		*c.cache = append(*c.cache, collaborator)
+		if c.index != nil {
+			c.index[collaborator.ID] = len(*c.cache) - 1
+		}
+	}
+}
+
+// removeAt removes the collaborator at the position, and shifts the positions of the following ones in the index.
+// The caller must hold the lock.
+func (c *collaboratorCache) removeAt(i int) {
+	collaborators := *c.cache
+	id := collaborators[i].ID
+	copy(collaborators[i:], collaborators[i+1:])
+	collaborators = collaborators[:len(collaborators)-1]
+	*c.cache = collaborators
+	if c.index != nil {
+		delete(c.index, id)
+		for j := i; j < len(collaborators); j++ {
+			c.index[collaborators[j].ID] = j
+		}
+	}
+}
+
+// storeState stores the membership identified by the project id and the user id.
+func (c *collaboratorCache) storeState(state CollaboratorState) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("collaborators")
+	var res []CollaboratorState
+	isNew := true
+	for _, s := range *c.states {
+		if s.ProjectID == state.ProjectID && s.UserID == state.UserID {
+			if !state.IsDeleted {
+				res = append(res, state)
+			}
+			isNew = false
+		} else {
+			res = append(res, s)
+		}
+	}
+	if isNew && !state.IsDeleted.Bool() {
+		res = append(res, state)
+	}
+	c.states = &res
+}
+
+// replace swaps the cache for the given collaborators and memberships at once.
+func (c *collaboratorCache) replace(collaborators []Collaborator, states []CollaboratorState) {
+	var res []Collaborator
+	for _, collaborator := range collaborators {
+		if !collaborator.IsDeleted.Bool() {
+			res = append(res, collaborator)
+		}
+	}
+	var resStates []CollaboratorState
+	for _, state := range states {
+		if !state.IsDeleted.Bool() {
+			resStates = append(resStates, state)
+		}
+	}
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("collaborators")
+	c.set(res)
+	c.states = &resStates
+}
+
+// Assign sets the collaborator responsible for the item.
+// If the memberships of the project of the cached item are known, the user must be
+// an active or invited collaborator of it, since the server ignores others silently.
+func (c *ItemClient) Assign(id, userID ID) error {
+	if userID.IsZero() {
+		return fmt.Errorf("assign requires a user id")
+	}
+	item := c.Resolve(id)
+	if item != nil {
+		if states := c.Collaborator.GetStates(item.ProjectID); len(states) != 0 {
+			ok := false
+			for _, state := range states {
+				if state.UserID == userID && state.State != CollaboratorStateDeleted && !state.IsDeleted.Bool() {
+					ok = true
+					break
+				}
+			}
+			if !ok {
+				return fmt.Errorf("user %s is not a collaborator of project %s", userID, item.ProjectID)
+			}
+		}
+	}
+	c.enqueue("item_update", map[string]interface{}{
+		"id":              id,
+		"responsible_uid": userID,
+	}, "")
+	if item != nil && c.optimistic() {
+		item.ResponsibleUID = userID
+		c.cache.store(*item)
+	}
+	return nil
+}
+
+// Unassign clears the collaborator responsible for the item.
+func (c *ItemClient) Unassign(id ID) error {
+	c.enqueue("item_update", map[string]interface{}{
+		"id":              id,
+		"responsible_uid": nil,
+	}, "")
+	if item := c.Resolve(id); item != nil && c.optimistic() {
+		item.ResponsibleUID = ""
+		c.cache.store(*item)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func TestProjectClient_Collaborators(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token",
+			"collaborators": [
+				{"id": 1, "email": "alice@example.com", "full_name": "Alice"},
+				{"id": 2, "email": "bob@example.com", "full_name": "Bob"}
+			],
+			"collaborator_states": [
+				{"project_id": 10, "user_id": 1, "state": "active"},
+				{"project_id": 10, "user_id": 2, "state": "deleted"},
+				{"project_id": 20, "user_id": 2, "state": "invited"}
+			]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+
+	collaborators := client.Project.Collaborators("10")
+	if len(collaborators) != 1 || collaborators[0].FullName != "Alice" {
+		t.Errorf("Unexpect collaborators: %v", collaborators)
+	}
+	if collaborators := client.Project.Collaborators("20"); len(collaborators) != 1 || collaborators[0].Email != "bob@example.com" {
+		t.Errorf("Unexpect collaborators: %v", collaborators)
+	}
+
+	if err := client.Project.DeleteCollaborator("10", "1"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Project.DeleteCollaborator("10", "3"); err == nil {
+		t.Error("Expect error for unknown collaborator, but got nil")
+	}
+	if err := client.Project.Share("10", "carol@example.com"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(client.queue) != 2 || client.queue[0].Type != "delete_collaborator" || client.queue[1].Type != "share_project" {
+		t.Errorf("Unexpect queue: %v", client.queue)
+	}
+	args := client.queue[0].Args.(map[string]interface{})
+	if args["email"] != "alice@example.com" {
+		t.Errorf("Expect %s, but got %v", "alice@example.com", args["email"])
+	}
+}
+
+func TestItemClient_Assign(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token",
+			"items": [
+				{"id": 100, "project_id": 10, "content": "shared"},
+				{"id": 200, "project_id": 30, "content": "private"}
+			],
+			"collaborators": [
+				{"id": 1, "email": "alice@example.com", "full_name": "Alice"},
+				{"id": 2, "email": "bob@example.com", "full_name": "Bob"}
+			],
+			"collaborator_states": [
+				{"project_id": 10, "user_id": 1, "state": "active"},
+				{"project_id": 10, "user_id": 2, "state": "deleted"}
+			]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+
+	if err := client.Item.Assign("100", "2"); err == nil {
+		t.Error("Expect error for a deleted collaborator, but got nil")
+	}
+	if err := client.Item.Assign("100", "1"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if item := client.Item.Resolve("100"); item.ResponsibleUID != "1" {
+		t.Errorf("Expect %s, but got %s", "1", item.ResponsibleUID)
+	}
+	// the project of the item is not shared as far as the cache knows.
+	if err := client.Item.Assign("200", "3"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.Unassign("100"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if item := client.Item.Resolve("100"); !item.ResponsibleUID.IsZero() {
+		t.Errorf("Expect empty, but got %s", item.ResponsibleUID)
+	}
+	if len(client.queue) != 3 {
+		t.Fatalf("Expect %d, but got %d", 3, len(client.queue))
+	}
+	b, err := json.Marshal(client.queue[2].Args)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := `{"id":"100","responsible_uid":null}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, b)
+	}
+}
@@ -0,0 +1,128 @@
+package todoist
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/fatih/color"
+	"strconv"
+)
+
+// Color is a color id of projects, labels and filters.
+type Color int
+
+const (
+	BerryRed   Color = 30
+	Red        Color = 31
+	Orange     Color = 32
+	Yellow     Color = 33
+	OliveGreen Color = 34
+	LimeGreen  Color = 35
+	Green      Color = 36
+	MintGreen  Color = 37
+	Teal       Color = 38
+	SkyBlue    Color = 39
+	LightBlue  Color = 40
+	Blue       Color = 41
+	Grape      Color = 42
+	Violet     Color = 43
+	Lavender   Color = 44
+	Magenta    Color = 45
+	Salmon     Color = 46
+	Charcoal   Color = 47
+	Grey       Color = 48
+	Taupe      Color = 49
+)
+
+var colorNames = map[Color]string{
+	BerryRed:   "berry_red",
+	Red:        "red",
+	Orange:     "orange",
+	Yellow:     "yellow",
+	OliveGreen: "olive_green",
+	LimeGreen:  "lime_green",
+	Green:      "green",
+	MintGreen:  "mint_green",
+	Teal:       "teal",
+	SkyBlue:    "sky_blue",
+	LightBlue:  "light_blue",
+	Blue:       "blue",
+	Grape:      "grape",
+	Violet:     "violet",
+	Lavender:   "lavender",
+	Magenta:    "magenta",
+	Salmon:     "salmon",
+	Charcoal:   "charcoal",
+	Grey:       "grey",
+	Taupe:      "taupe",
+}
+
+// NewColor returns the color of the given name, e.g. "berry_red".
+func NewColor(name string) (Color, error) {
+	for c, n := range colorNames {
+		if n == name {
+			return c, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid color: %s", name)
+}
+
+func (c Color) IsValid() bool {
+	_, ok := colorNames[c]
+	return ok
+}
+
+func (c Color) String() string {
+	if n, ok := colorNames[c]; ok {
+		return n
+	}
+	return strconv.Itoa(int(c))
+}
+
+// attribute returns the closest terminal color.
+func (c Color) attribute() color.Attribute {
+	switch c {
+	case 30, 31:
+		return color.FgHiRed
+	case 32, 33:
+		return color.FgHiYellow
+	case 34, 35, 36:
+		return color.FgHiGreen
+	case 37, 38, 39:
+		return color.FgHiCyan
+	case 40, 41, 42:
+		return color.FgHiBlue
+	case 43, 44, 45, 46:
+		return color.FgHiMagenta
+	case 47, 48, 49:
+		return color.FgHiBlack
+	default:
+		return color.FgWhite
+	}
+}
+
+func (c Color) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Itoa(int(c))), nil
+}
+
+// UnmarshalJSON accepts both of an integer id and a string id like "berry_red".
+func (c *Color) UnmarshalJSON(b []byte) error {
+	var i int
+	if err := json.Unmarshal(b, &i); err == nil {
+		*c = Color(i)
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("Could not unmarshal into color: %s", string(b))
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		*c = Color(i)
+		return nil
+	}
+	color, err := NewColor(s)
+	if err != nil {
+		return err
+	}
+	*c = color
+	return nil
+}
@@ -0,0 +1,34 @@
+package todoist
+
+import "testing"
+
+func TestColor_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		s string
+		v Color
+	}{
+		{"30", BerryRed},
+		{`"49"`, Taupe},
+		{`"berry_red"`, BerryRed},
+	}
+	for _, test := range tests {
+		var v Color
+		if err := v.UnmarshalJSON([]byte(test.s)); err != nil || v != test.v {
+			t.Errorf("Expect %s, but got %s", test.v, v)
+		}
+	}
+	var v Color
+	if err := v.UnmarshalJSON([]byte(`"invalid"`)); err == nil {
+		t.Error("Expect error, but no error")
+	}
+}
+
+func TestColor_MarshalJSON(t *testing.T) {
+	b, err := BerryRed.MarshalJSON()
+	if err != nil || string(b) != "30" {
+		t.Errorf("Expect %s, but got %s", "30", string(b))
+	}
+	if BerryRed.String() != "berry_red" {
+		t.Errorf("Expect %s, but got %s", "berry_red", BerryRed.String())
+	}
+}
@@ -1,7 +1,13 @@
 package todoist
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
 
+// IntBool is a flag the API has sent as 0/1, true/false and strings of them.
+// It is marshaled as true/false as the API v9 does. null is unmarshaled as false, as a missing field is.
 type IntBool bool
 
 func (i IntBool) Bool() bool {
@@ -14,17 +20,21 @@ func (i IntBool) Bool() bool {
 
 func (i IntBool) MarshalJSON() ([]byte, error) {
 	if i {
-		return []byte("1"), nil
+		return []byte("true"), nil
 	} else {
-		return []byte("0"), nil
+		return []byte("false"), nil
 	}
 }
 
 func (i *IntBool) UnmarshalJSON(b []byte) (err error) {
-	switch string(b) {
-	case "1":
+	s := string(b)
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+	switch s {
+	case "1", "true":
 		*i = true
-	case "0":
+	case "0", "false", "null", "":
 		*i = false
 	default:
 		return fmt.Errorf("Could not unmarshal into intbool: %s", string(b))
@@ -32,6 +42,74 @@ func (i *IntBool) UnmarshalJSON(b []byte) (err error) {
 	return nil
 }
 
+// validateName rejects an empty or whitespace-only name of the resource,
+// which the server would reject at the commit.
+func validateName(resource, name string) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return fmt.Errorf("%s name must not be empty", resource)
+	}
+	return nil
+}
+
+// validateNameField validates the "name" of fields for UpdateFields, if it is given.
+func validateNameField(resource string, fields map[string]interface{}) error {
+	v, ok := fields["name"]
+	if !ok {
+		return nil
+	}
+	name, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("%s name must be a string, but got %T", resource, v)
+	}
+	return validateName(resource, name)
+}
+
+// DeleteOpts specifies how DeleteIfExists handles an id not in the cache.
+type DeleteOpts struct {
+	// ErrorIfNotFound makes DeleteIfExists return *NotFoundError instead of nil.
+	ErrorIfNotFound bool
+}
+
+// notFound returns the error of DeleteIfExists for the id not in the cache.
+func (opts *DeleteOpts) notFound(resource string, id ID) error {
+	if opts != nil && opts.ErrorIfNotFound {
+		return &NotFoundError{Resource: resource, ID: id}
+	}
+	return nil
+}
+
+// updateArgs returns the args of an update command for the fields, which must be in allowed.
+// Values are encoded as in the structs, e.g. IntBool for flags.
+func updateArgs(id ID, fields map[string]interface{}, allowed []string) (map[string]interface{}, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("require fields to update")
+	}
+	args := map[string]interface{}{"id": id}
+	for k, v := range fields {
+		ok := false
+		for _, a := range allowed {
+			if k == a {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("field %s can not be updated alone, allowed: %v", k, allowed)
+		}
+		args[k] = v
+	}
+	return args, nil
+}
+
+// mergeFields decodes the fields into v, keeping the other fields as is.
+func mergeFields(v interface{}, fields map[string]interface{}) error {
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
 type ColorStringer interface {
 	String() string
 	ColorString() string
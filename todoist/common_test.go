@@ -1,9 +1,12 @@
 package todoist
 
-import "testing"
+import (
+	"encoding/json"
+	"testing"
+)
 
 func TestIntBool_MarshalJSON(t *testing.T) {
-	s := "0"
+	s := "false"
 	v := IntBool(false)
 	b, err := v.MarshalJSON()
 	if err != nil || string(b) != s {
@@ -25,3 +28,52 @@ func TestIntBool_UnmarshalJSON(t *testing.T) {
 		t.Error("Expect error, but no error")
 	}
 }
+
+func TestIntBool_UnmarshalJSONShapes(t *testing.T) {
+	for _, c := range []struct {
+		in     string
+		expect IntBool
+	}{
+		{`1`, true},
+		{`0`, false},
+		{`true`, true},
+		{`false`, false},
+		{`"1"`, true},
+		{`"0"`, false},
+		{`"true"`, true},
+		{`"false"`, false},
+		{`""`, false},
+		{`null`, false},
+	} {
+		v := IntBool(!c.expect)
+		if err := v.UnmarshalJSON([]byte(c.in)); err != nil {
+			t.Errorf("Unexpect error for %s: %s", c.in, err)
+		} else if v != c.expect {
+			t.Errorf("Expect %v for %s, but got %v", c.expect, c.in, v)
+		}
+	}
+	for _, in := range []string{`2`, `"yes"`, `{}`, `[]`} {
+		var v IntBool
+		if err := v.UnmarshalJSON([]byte(in)); err == nil {
+			t.Errorf("Expect error for %s, but no error", in)
+		}
+	}
+
+	// the flag is decoded the same either way, so that the cache drops deleted entities.
+	for _, in := range []string{`{"id": 1, "is_deleted": 1}`, `{"id": 1, "is_deleted": true}`, `{"id": 1, "is_deleted": "1"}`} {
+		var section Section
+		if err := json.Unmarshal([]byte(in), &section); err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		if !section.IsDeleted.Bool() {
+			t.Errorf("Expect deleted for %s, but got %v", in, section.IsDeleted)
+		}
+	}
+	b, err := json.Marshal(Entity{ID: "1", IsDeleted: true})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := `{"id":"1","is_deleted":true}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, b)
+	}
+}
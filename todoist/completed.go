@@ -3,81 +3,29 @@ package todoist
 import (
 	"context"
 	"net/url"
+	"strconv"
+	"time"
 )
 
-type Stats struct {
-	KarmaLastUpdate float64 `json:"karma_last_update"`
-	KarmaTrend      string  `json:"karma_trend"`
-	DaysItems       []struct {
-		Date  string `json:"date"`
-		Items []struct {
-			Completed int `json:"completed"`
-			ID        int `json:"id"`
-		} `json:"items"`
-		TotalCompleted int `json:"total_completed"`
-	} `json:"days_items"`
-	CompletedCount     int `json:"completed_count"`
-	KarmaUpdateReasons []struct {
-		PositiveKarmaReasons []int   `json:"positive_karma_reasons"`
-		NewKarma             float64 `json:"new_karma"`
-		NegativeKarma        float64 `json:"negative_karma"`
-		PositiveKarma        float64 `json:"positive_karma"`
-		NegativeKarmaReasons []int   `json:"negative_karma_reasons"`
-		Time                 string  `json:"time"`
-	} `json:"karma_update_reasons"`
-	Karma     float64 `json:"karma"`
-	WeekItems []struct {
-		Date  string `json:"date"`
-		Items []struct {
-			Completed int `json:"completed"`
-			ID        int `json:"id"`
-		} `json:"items"`
-		TotalCompleted int `json:"total_completed"`
-	} `json:"week_items"`
-	KarmaGraph string `json:"karma_graph"`
-	Goals      struct {
-		KarmaDisabled   int `json:"karma_disabled"`
-		UserID          int `json:"user_id"`
-		LastDailyStreak struct {
-			Count int    `json:"count"`
-			Start string `json:"start"`
-			End   string `json:"end"`
-		} `json:"last_daily_streak"`
-		VacationMode    int   `json:"vacation_mode"`
-		IgnoreDays      []int `json:"ignore_days"`
-		MaxWeeklyStreak struct {
-			Count int    `json:"count"`
-			Start string `json:"start"`
-			End   string `json:"end"`
-		} `json:"max_weekly_streak"`
-		CurrentWeeklyStreak struct {
-			Count int    `json:"count"`
-			Start string `json:"start"`
-			End   string `json:"end"`
-		} `json:"current_weekly_streak"`
-		CurrentDailyStreak struct {
-			Count int    `json:"count"`
-			Start string `json:"start"`
-			End   string `json:"end"`
-		} `json:"current_daily_streak"`
-		LastWeeklyStreak struct {
-			Count int    `json:"count"`
-			Start string `json:"start"`
-			End   string `json:"end"`
-		} `json:"last_weekly_streak"`
-		WeeklyGoal     int `json:"weekly_goal"`
-		MaxDailyStreak struct {
-			Count int    `json:"count"`
-			Start string `json:"start"`
-			End   string `json:"end"`
-		} `json:"max_daily_streak"`
-		DailyGoal int `json:"daily_goal"`
-	} `json:"goals"`
-}
-
+// CompletedItems is the items completed in history, with their projects.
+// The ID of an item here is the id of the completion record, not the id of the item.
+// Use TaskID to refer to the item, e.g. by ItemClient.UncompleteByTaskID,
+// as a recurring item is completed many times with the same TaskID.
 type CompletedItems struct {
 	Items    []Item         `json:"items"`
 	Projects map[ID]Project `json:"projects"`
+	// Total is the number of the items matched by the options in all pages. It is set by GetAllCompleted,
+	// and by GetCompleted with CompletedOpts.CountTotal.
+	Total int `json:"-"`
+}
+
+// ProjectName returns the name of the project the completed item belongs to, from Projects.
+// It returns an empty string if the project is not included, e.g. it is deleted.
+func (c *CompletedItems) ProjectName(item Item) string {
+	if project, ok := c.Projects[item.ProjectID]; ok {
+		return project.Name
+	}
+	return ""
 }
 
 func (c *CompletedItems) GroupByCompletedDate() map[string][]Item {
@@ -94,30 +42,167 @@ type CompletedClient struct {
 	*Client
 }
 
-func (c *CompletedClient) GetStats() (*Stats, error) {
-	req, err := c.newRequest(context.Background(), "POST", "completed/get_stats", url.Values{})
+// GetStats is the same as StatsClient.Get.
+func (c *CompletedClient) GetStats(ctx context.Context) (*Stats, error) {
+	return c.Client.Stats.Get(ctx)
+}
+
+func (c *CompletedClient) GetAll(ctx context.Context) (*CompletedItems, error) {
+	req, err := c.newRequest(ctx, "POST", pathCompletedGetAll, url.Values{})
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
-	var out Stats
-	decodeBody(res, &out)
+	var out CompletedItems
+	if err = c.decodeBody(res, &out); err != nil {
+		return nil, err
+	}
 	return &out, nil
 }
 
-func (c *CompletedClient) GetAll() (*CompletedItems, error) {
-	req, err := c.newRequest(context.Background(), "POST", "completed/get_all", url.Values{})
+// maxCompletedLimit is the maximum number of completed items returned in a request.
+const maxCompletedLimit = 200
+
+type CompletedOpts struct {
+	ProjectID ID
+	SectionID ID
+	Since     time.Time
+	Until     time.Time
+	// Limit defaults to maxCompletedLimit.
+	Limit  int
+	Offset int
+	// CountTotal makes GetCompleted count the items of the following pages to set Total,
+	// a request per 200 items up to maxCompletedCount items after the page.
+	CountTotal bool
+}
+
+// limit returns Limit, or maxCompletedLimit if it is not given or exceeds it.
+func (o CompletedOpts) limit() int {
+	if o.Limit <= 0 || o.Limit > maxCompletedLimit {
+		return maxCompletedLimit
+	}
+	return o.Limit
+}
+
+func (o CompletedOpts) values() url.Values {
+	const layout = "2006-01-02T15:04"
+	values := url.Values{}
+	if !o.ProjectID.IsZero() {
+		values.Set("project_id", o.ProjectID.String())
+	}
+	if !o.SectionID.IsZero() {
+		values.Set("section_id", o.SectionID.String())
+	}
+	if !o.Since.IsZero() {
+		values.Set("since", o.Since.UTC().Format(layout))
+	}
+	if !o.Until.IsZero() {
+		values.Set("until", o.Until.UTC().Format(layout))
+	}
+	values.Set("limit", strconv.Itoa(o.limit()))
+	values.Set("offset", strconv.Itoa(o.Offset))
+	return values
+}
+
+// GetCompleted returns a page of completed items. Fetch the next page by increasing opts.Offset
+// while the page is full. The server does not report the total, so Total is set only with
+// opts.CountTotal, by requesting the following pages as CompletedCount does if the page is full.
+func (c *CompletedClient) GetCompleted(ctx context.Context, opts CompletedOpts) (*CompletedItems, error) {
+	res, err := c.getCompleted(ctx, opts)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	if !opts.CountTotal {
+		return res, nil
+	}
+	res.Total = opts.Offset + len(res.Items)
+	if len(res.Items) == opts.limit() {
+		opts.Offset = res.Total
+		rest, err := c.count(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		res.Total += rest
+	}
+	return res, nil
+}
+
+// getCompleted returns a page of completed items without the total count.
+func (c *CompletedClient) getCompleted(ctx context.Context, opts CompletedOpts) (*CompletedItems, error) {
+	req, err := c.newRequest(ctx, "POST", pathCompletedGetAll, opts.values())
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	var out CompletedItems
-	decodeBody(res, &out)
+	if err = c.decodeBody(res, &out); err != nil {
+		return nil, err
+	}
 	return &out, nil
 }
+
+// GetAllCompleted returns completed items of all pages from opts.Offset. Total is opts.Offset plus the number of them.
+// CountTotal is not needed, since every page is fetched anyway.
+func (c *CompletedClient) GetAllCompleted(ctx context.Context, opts CompletedOpts) (*CompletedItems, error) {
+	opts.Limit = opts.limit()
+	res := CompletedItems{Projects: map[ID]Project{}}
+	for {
+		page, err := c.getCompleted(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		res.Items = append(res.Items, page.Items...)
+		for id, project := range page.Projects {
+			res.Projects[id] = project
+		}
+		if len(page.Items) < opts.Limit {
+			res.Total = opts.Offset + len(page.Items)
+			return &res, nil
+		}
+		opts.Offset += len(page.Items)
+	}
+}
+
+// maxCompletedCount caps the count of completed items, since the server does not
+// report the total and the items are fetched page by page to count them.
+const maxCompletedCount = 10 * maxCompletedLimit
+
+// count returns the number of completed items matched by opts, up to maxCompletedCount.
+func (c *CompletedClient) count(ctx context.Context, opts CompletedOpts) (int, error) {
+	opts.Limit = maxCompletedLimit
+	n := 0
+	for n < maxCompletedCount {
+		page, err := c.getCompleted(ctx, opts)
+		if err != nil {
+			return 0, err
+		}
+		n += len(page.Items)
+		if len(page.Items) < opts.Limit {
+			break
+		}
+		opts.Offset += len(page.Items)
+	}
+	if n > maxCompletedCount {
+		n = maxCompletedCount
+	}
+	return n, nil
+}
+
+// CompletedCount returns the number of completed items in the project.
+// It fetches the completed items to count them, a request per 200 items,
+// so the count is capped at 2000 items to bound the cost.
+func (c *ProjectClient) CompletedCount(ctx context.Context, id ID) (int, error) {
+	return c.Completed.count(ctx, CompletedOpts{ProjectID: id})
+}
+
+// CompletedCount returns the number of completed items in the section.
+// It costs the same as ProjectClient.CompletedCount, and is capped at 2000 items as well.
+func (c *SectionClient) CompletedCount(ctx context.Context, id ID) (int, error) {
+	return c.Completed.count(ctx, CompletedOpts{SectionID: id})
+}
@@ -0,0 +1,189 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCompletedClient_GetAllCompleted(t *testing.T) {
+	var offsets, limits []string
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		offsets = append(offsets, r.FormValue("offset"))
+		limits = append(limits, r.FormValue("limit"))
+		offset, _ := strconv.Atoi(r.FormValue("offset"))
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		var items []map[string]interface{}
+		for i := offset; i < offset+limit && i < 5; i++ {
+			items = append(items, map[string]interface{}{"id": i + 1, "content": "item"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	})
+	defer teardown()
+
+	completed, err := client.Completed.GetAllCompleted(context.Background(), CompletedOpts{Limit: 2})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(completed.Items) != 5 {
+		t.Errorf("Expect %d items, but got %d", 5, len(completed.Items))
+	}
+	if len(offsets) != 3 || offsets[2] != "4" {
+		t.Errorf("Unexpect offsets: %v", offsets)
+	}
+	if expect := []string{"2", "2", "2"}; !reflect.DeepEqual(limits, expect) {
+		t.Errorf("Expect limits %v, but got %v", expect, limits)
+	}
+	if completed.Total != 5 {
+		t.Errorf("Expect %d, but got %d", 5, completed.Total)
+	}
+
+	offsets = nil
+	page, err := client.Completed.GetCompleted(context.Background(), CompletedOpts{Limit: 2})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(page.Items) != 2 || page.Total != 0 || len(offsets) != 1 {
+		t.Errorf("Expect a page without counting, but got %d items of %d with %d requests", len(page.Items), page.Total, len(offsets))
+	}
+	offsets = nil
+	if page, err = client.Completed.GetCompleted(context.Background(), CompletedOpts{Limit: 2, CountTotal: true}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(page.Items) != 2 || page.Total != 5 {
+		t.Errorf("Expect %d items of %d, but got %d of %d", 2, 5, len(page.Items), page.Total)
+	}
+	offsets = nil
+	if page, err = client.Completed.GetCompleted(context.Background(), CompletedOpts{Limit: 2, Offset: 4, CountTotal: true}); err != nil || page.Total != 5 || len(offsets) != 1 {
+		t.Errorf("Expect the last page without counting, but got %v with %d requests (%v)", page, len(offsets), err)
+	}
+}
+
+func TestCompletedClient_GetAllErrors(t *testing.T) {
+	var body string
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if body == "Bad Request" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write([]byte(body))
+	})
+	defer teardown()
+
+	body = `<html>Bad Gateway</html>`
+	var decodeErr *DecodeError
+	if res, err := client.Completed.GetAll(context.Background()); res != nil || !errors.As(err, &decodeErr) {
+		t.Errorf("Expect DecodeError, but got %v (%v)", err, res)
+	}
+	body = "Bad Request"
+	var apiErr *APIError
+	if res, err := client.Completed.GetAll(context.Background()); res != nil || !errors.As(err, &apiErr) {
+		t.Errorf("Expect APIError, but got %v (%v)", err, res)
+	}
+	body = `{"items": [{"id": 1, "content": "item"}]}`
+	if res, err := client.Completed.GetAll(context.Background()); err != nil || len(res.Items) != 1 {
+		t.Errorf("Expect %d item, but got %v (%v)", 1, res, err)
+	}
+}
+
+func TestProjectClient_CompletedCount(t *testing.T) {
+	var requests int
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		total := 250
+		if r.FormValue("project_id") == "2" {
+			total = 5000
+		}
+		offset, _ := strconv.Atoi(r.FormValue("offset"))
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+		var items []map[string]interface{}
+		for i := offset; i < offset+limit && i < total; i++ {
+			items = append(items, map[string]interface{}{"id": i + 1, "content": "item"})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": items})
+	})
+	defer teardown()
+
+	n, err := client.Project.CompletedCount(context.Background(), "1")
+	if err != nil || n != 250 || requests != 2 {
+		t.Errorf("Expect %d with %d requests, but got %d with %d (%v)", 250, 2, n, requests, err)
+	}
+	requests = 0
+	n, err = client.Project.CompletedCount(context.Background(), "2")
+	if err != nil || n != maxCompletedCount || requests != 10 {
+		t.Errorf("Expect %d with %d requests, but got %d with %d (%v)", maxCompletedCount, 10, n, requests, err)
+	}
+}
+
+func TestItemClient_UncompleteByTaskID(t *testing.T) {
+	var commands []Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/completed/get_all" {
+			w.Write([]byte(`{"items": [{"id": 900, "task_id": 100, "content": "revived", "completed_at": "2020-01-02T03:04:05Z"}]}`))
+			return
+		}
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		w.Write([]byte(`{"sync_token": "next-token", "sync_status": {"` + string(commands[0].UUID) + `": "ok"},
+			"items": [{"id": 100, "project_id": 1, "content": "revived"}]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	completed, err := client.Completed.GetCompleted(context.Background(), CompletedOpts{})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(completed.Items) != 1 || completed.Items[0].TaskID != "100" {
+		t.Fatalf("Unexpect items: %v", completed.Items)
+	}
+	if err = client.Item.UncompleteByTaskID(completed.Items[0].TaskID); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err = client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(commands) != 1 || commands[0].Type != "item_uncomplete" {
+		t.Fatalf("Unexpect commands: %v", commands)
+	}
+	if args := commands[0].Args.(map[string]interface{}); args["id"] != "100" {
+		t.Errorf("Expect %d, but got %v", 100, args["id"])
+	}
+	if item := client.Item.Resolve("100"); item == nil || item.IsChecked() {
+		t.Errorf("Unexpect cached item: %v", item)
+	}
+	if item := client.Item.Resolve("900"); item != nil {
+		t.Errorf("Expect nil, but got %v", item)
+	}
+}
+
+func TestCompletedItems_ProjectName(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{
+			"items": [
+				{"id": 900, "task_id": 100, "project_id": 1, "content": "a"},
+				{"id": 901, "task_id": 101, "project_id": 2, "content": "b"}
+			],
+			"projects": {"1": {"id": 1, "name": "Work"}}}`))
+	})
+	defer teardown()
+
+	completed, err := client.Completed.GetCompleted(context.Background(), CompletedOpts{})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if name := completed.ProjectName(completed.Items[0]); name != "Work" {
+		t.Errorf("Expect %s, but got %s", "Work", name)
+	}
+	if name := completed.ProjectName(completed.Items[1]); name != "" {
+		t.Errorf("Expect empty, but got %s", name)
+	}
+	var empty CompletedItems
+	if name := empty.ProjectName(completed.Items[0]); name != "" {
+		t.Errorf("Expect empty, but got %s", name)
+	}
+}
@@ -0,0 +1,170 @@
+package todoist
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	dueClockPattern = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	dueWeekdays     = map[string]time.Weekday{
+		"sunday": time.Sunday, "sun": time.Sunday,
+		"monday": time.Monday, "mon": time.Monday,
+		"tuesday": time.Tuesday, "tue": time.Tuesday,
+		"wednesday": time.Wednesday, "wed": time.Wednesday,
+		"thursday": time.Thursday, "thu": time.Thursday,
+		"friday": time.Friday, "fri": time.Friday,
+		"saturday": time.Saturday, "sat": time.Saturday,
+	}
+)
+
+// ParseDueString interprets the due string s in English without the server, e.g. to preview a due
+// before sending it. It is a small subset of the parser of Todoist, and may differ from it:
+//
+//	today, tod, tomorrow, tom                the date
+//	monday, mon, ...                         the next weekday after today
+//	in 3 days, in 2 weeks                    days after today
+//	2020-01-02                               the date
+//	every day, daily, every week, weekly     recurring from today
+//	every 3 days, every 2 weeks              recurring from today
+//	every monday, every mon, ...             recurring from the weekday on or after today
+//
+// Any of them may end with a time, e.g. "tomorrow 9am", "every monday at 21:30", and a time alone
+// is today. A due with a time is in loc, or floating in the timezone of now if loc is nil or local.
+// For anything else, it returns a due with s as String and without date, so that the server parses it.
+func ParseDueString(s string, loc *time.Location, now time.Time) (*Due, error) {
+	if len(strings.TrimSpace(s)) == 0 {
+		return nil, errors.New("parse due string requires a string")
+	}
+	zoned := loc != nil && loc != time.Local
+	if loc == nil {
+		loc = now.Location()
+	}
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	due := Due{String: s, Lang: "en"}
+	words := strings.Fields(strings.ToLower(s))
+	switch words[0] {
+	case "every":
+		due.IsRecurring = true
+		words = words[1:]
+	case "daily", "weekly":
+		due.IsRecurring = true
+		words[0] = map[string]string{"daily": "day", "weekly": "week"}[words[0]]
+	}
+
+	hour, min, hasClock := -1, 0, false
+	if n := len(words); n != 0 {
+		if h, m, ok := parseDueClock(words[n-1]); ok {
+			hour, min, hasClock = h, m, true
+			words = words[:n-1]
+			if n := len(words); n != 0 && words[n-1] == "at" {
+				words = words[:n-1]
+			}
+		}
+	}
+
+	date, ok := parseDueDate(words, today, due.IsRecurring, hasClock)
+	if !ok {
+		return &Due{String: s}, nil
+	}
+	if !hasClock {
+		due.Date = Time{date}
+		due.layout = dateLayout
+		return &due, nil
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), hour, min, 0, 0, loc)
+	due.Date = Time{date}
+	if zoned {
+		due.Timezone = loc.String()
+		due.layout = datetimeTzLayout
+	} else {
+		due.layout = datetimeLayout
+	}
+	return &due, nil
+}
+
+// parseDueClock parses a time as "9am", "9:30pm" or "21:30", and returns the hour and the minute.
+func parseDueClock(word string) (int, int, bool) {
+	m := dueClockPattern.FindStringSubmatch(word)
+	if m == nil || (len(m[2]) == 0 && len(m[3]) == 0) {
+		return 0, 0, false
+	}
+	hour, _ := strconv.Atoi(m[1])
+	min := 0
+	if len(m[2]) != 0 {
+		min, _ = strconv.Atoi(m[2])
+	}
+	switch m[3] {
+	case "am", "pm":
+		if hour < 1 || hour > 12 {
+			return 0, 0, false
+		}
+		hour %= 12
+		if m[3] == "pm" {
+			hour += 12
+		}
+	}
+	if hour > 23 || min > 59 {
+		return 0, 0, false
+	}
+	return hour, min, true
+}
+
+// parseDueDate returns the date the words mean from today, or the first date of the recurrence.
+func parseDueDate(words []string, today time.Time, recurring, hasClock bool) (time.Time, bool) {
+	switch len(words) {
+	case 0:
+		return today, hasClock && !recurring
+	case 1:
+		word := words[0]
+		if weekday, ok := dueWeekdays[word]; ok {
+			days := (int(weekday) - int(today.Weekday()) + 7) % 7
+			if days == 0 && !recurring {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), true
+		}
+		if recurring {
+			return today, word == "day" || word == "week"
+		}
+		switch word {
+		case "today", "tod":
+			return today, true
+		case "tomorrow", "tom":
+			return today.AddDate(0, 0, 1), true
+		}
+		if t, err := time.ParseInLocation(dateLayout, word, today.Location()); err == nil {
+			return t, true
+		}
+	case 2, 3:
+		if !recurring {
+			if len(words) != 3 || words[0] != "in" {
+				return time.Time{}, false
+			}
+			words = words[1:]
+		} else if len(words) != 2 {
+			return time.Time{}, false
+		}
+		n, err := strconv.Atoi(words[0])
+		if err != nil || n < 1 {
+			return time.Time{}, false
+		}
+		switch strings.TrimSuffix(words[1], "s") {
+		case "day":
+		case "week":
+			n *= 7
+		default:
+			return time.Time{}, false
+		}
+		if recurring {
+			return today, true
+		}
+		return today.AddDate(0, 0, n), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,85 @@
+package todoist
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDueString(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skip(err)
+	}
+	// Wednesday
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, madrid)
+	for _, test := range []struct {
+		s         string
+		date      string
+		recurring bool
+	}{
+		{"today", "2020-01-01", false},
+		{"Tomorrow", "2020-01-02", false},
+		{"tom 9am", "2020-01-02T09:00:00+01:00", false},
+		{"friday", "2020-01-03", false},
+		{"wed", "2020-01-08", false},
+		{"in 3 days", "2020-01-04", false},
+		{"in 2 weeks", "2020-01-15", false},
+		{"2020-02-29 at 21:30", "2020-02-29T21:30:00+01:00", false},
+		{"12pm", "2020-01-01T12:00:00+01:00", false},
+		{"every day", "2020-01-01", true},
+		{"daily at 12am", "2020-01-01T00:00:00+01:00", true},
+		{"every 2 weeks", "2020-01-01", true},
+		{"every wednesday", "2020-01-01", true},
+		{"every monday 9:15am", "2020-01-06T09:15:00+01:00", true},
+	} {
+		due, err := ParseDueString(test.s, madrid, now)
+		if err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		layout := dateLayout
+		if len(test.date) != len(dateLayout) {
+			layout = time.RFC3339
+			if due.Timezone != "Europe/Madrid" {
+				t.Errorf("Expect %s, but got %s", "Europe/Madrid", due.Timezone)
+			}
+		}
+		if date := due.Date.Time.Format(layout); date != test.date {
+			t.Errorf("Expect %s, but got %s for %s", test.date, date, test.s)
+		}
+		if due.IsRecurring != test.recurring || due.String != test.s {
+			t.Errorf("Unexpect due for %s: %v", test.s, due)
+		}
+	}
+
+	for _, s := range []string{"every other day", "next monday", "in 3 months", "13pm", "every 9am"} {
+		due, err := ParseDueString(s, madrid, now)
+		if err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		if !due.Date.IsZero() || due.String != s {
+			t.Errorf("Expect raw due for %s, but got %v", s, due)
+		}
+	}
+
+	if _, err := ParseDueString(" ", madrid, now); err == nil {
+		t.Error("Expect error, but got nil")
+	}
+}
+
+func TestParseDueString_Floating(t *testing.T) {
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	due, err := ParseDueString("tomorrow 9am", nil, now)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if !due.IsFloating() || len(due.Timezone) != 0 {
+		t.Errorf("Expect floating due, but got %v", due)
+	}
+	due, err = ParseDueString("tomorrow", nil, now)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if !due.IsFullDay() {
+		t.Errorf("Expect full-day due, but got %v", due)
+	}
+}
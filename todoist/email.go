@@ -0,0 +1,73 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type emailResponse struct {
+	ObjID ID     `json:"obj_id"`
+	Email string `json:"email"`
+}
+
+// emailAddress returns the forwarding address of the object, creating it if disabled.
+// Addresses are not cached, since they change when they are regenerated.
+func (c *Client) emailAddress(ctx context.Context, objType string, id ID) (string, error) {
+	values := url.Values{"obj_type": {objType}, "obj_id": {id.String()}}
+	req, err := c.newRequest(ctx, http.MethodPost, pathEmailsGetOrCreate, values)
+	if err != nil {
+		return "", err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return "", err
+	}
+	if (res.StatusCode / 100) != 2 {
+		return "", newAPIError(res)
+	}
+	var out emailResponse
+	if err = c.decodeBody(res, &out); err != nil {
+		return "", err
+	}
+	return out.Email, nil
+}
+
+func (c *Client) disableEmail(ctx context.Context, objType string, id ID) error {
+	values := url.Values{"obj_type": {objType}, "obj_id": {id.String()}}
+	req, err := c.newRequest(ctx, http.MethodPost, pathEmailsDisable, values)
+	if err != nil {
+		return err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	if (res.StatusCode / 100) != 2 {
+		return newAPIError(res)
+	}
+	res.Body.Close()
+	return nil
+}
+
+// EmailAddress returns the address to add items to the project by email.
+// The address is enabled if it is disabled.
+func (c *ProjectClient) EmailAddress(ctx context.Context, id ID) (string, error) {
+	return c.emailAddress(ctx, "project", id)
+}
+
+// DisableEmail disables the address of the project. EmailAddress enables a new one.
+func (c *ProjectClient) DisableEmail(ctx context.Context, id ID) error {
+	return c.disableEmail(ctx, "project", id)
+}
+
+// EmailAddress returns the address to add notes to the item by email.
+// The address is enabled if it is disabled.
+func (c *ItemClient) EmailAddress(ctx context.Context, id ID) (string, error) {
+	return c.emailAddress(ctx, "item", id)
+}
+
+// DisableEmail disables the address of the item. EmailAddress enables a new one.
+func (c *ItemClient) DisableEmail(ctx context.Context, id ID) error {
+	return c.disableEmail(ctx, "item", id)
+}
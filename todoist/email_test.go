@@ -0,0 +1,45 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestProjectClient_EmailAddress(t *testing.T) {
+	requests := 0
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if objType := r.FormValue("obj_type"); objType != "project" {
+			t.Errorf("Expect %s, but got %s", "project", objType)
+		}
+		if objID := r.FormValue("obj_id"); objID != "10" {
+			t.Errorf("Expect %s, but got %s", "10", objID)
+		}
+		switch r.URL.Path {
+		case "/emails/get_or_create":
+			w.Write([]byte(`{"obj_id": 10, "email": "add.task.10.abcdef@todoist.net"}`))
+		case "/emails/disable":
+			w.Write([]byte(`{}`))
+		default:
+			t.Errorf("Unexpect path: %s", r.URL.Path)
+		}
+	})
+	defer teardown()
+
+	for i := 0; i < 2; i++ {
+		email, err := client.Project.EmailAddress(context.Background(), "10")
+		if err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		if email != "add.task.10.abcdef@todoist.net" {
+			t.Errorf("Expect %s, but got %s", "add.task.10.abcdef@todoist.net", email)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("Expect address not to be cached, but got %d requests", requests)
+	}
+	if err := client.Project.DisableEmail(context.Background(), "10"); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+}
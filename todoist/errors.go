@@ -0,0 +1,108 @@
+package todoist
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// TransportError is returned when a request fails without a response, e.g. by a network timeout.
+// Such a request may be retried. The API token is redacted from URL, and from Err if it is *url.Error.
+type TransportError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *TransportError) Error() string {
+	err := e.Err
+	if urlErr, ok := err.(*url.Error); ok {
+		// url.Error repeats the URL.
+		err = urlErr.Err
+	}
+	return fmt.Sprintf("failed to request %s %s: %s", e.Method, e.URL, err)
+}
+
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// DecodeError is returned when a successful response is not decoded, e.g. an HTML page of a proxy.
+// Body is the raw body of the response.
+type DecodeError struct {
+	URL        string
+	StatusCode int
+	Body       []byte
+	Err        error
+}
+
+func (e *DecodeError) Error() string {
+	snippet := string(e.Body)
+	if len(snippet) > maxBodySnippet {
+		snippet = snippet[:maxBodySnippet] + "..."
+	}
+	return fmt.Sprintf("failed to decode response of %s (status code: %d): %s, body: %s", e.URL, e.StatusCode, e.Err, snippet)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// APIError is returned when the server rejects a request with an error status code.
+// Err is the error in the body as *CommandError if the body has one, so that errors.Is matches
+// it with the sentinel errors, e.g. ErrLimitsReached. A 4xx error is not solved by retries.
+type APIError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       []byte
+	Err        error
+}
+
+func (e *APIError) Error() string {
+	if commandErr, ok := e.Err.(*CommandError); ok {
+		return fmt.Sprintf("%s %s failed with status code %d: %s (error code: %d)", e.Method, e.URL, e.StatusCode, commandErr.Message, commandErr.Code)
+	}
+	snippet := string(e.Body)
+	if len(snippet) > maxBodySnippet {
+		snippet = snippet[:maxBodySnippet] + "..."
+	}
+	return fmt.Sprintf("%s %s failed with status code %d, body: %s", e.Method, e.URL, e.StatusCode, snippet)
+}
+
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// newAPIError returns *APIError of the response with an error status code, and closes the body.
+func newAPIError(res *http.Response) error {
+	defer res.Body.Close()
+	b, _ := ioutil.ReadAll(res.Body)
+	apiErr := &APIError{StatusCode: res.StatusCode, Body: b}
+	if res.Request != nil {
+		apiErr.Method = res.Request.Method
+		apiErr.URL = redactURL(res.Request.URL)
+	}
+	var commandErr CommandError
+	if err := json.Unmarshal(b, &commandErr); err == nil && len(commandErr.Message) != 0 {
+		apiErr.Err = &commandErr
+	}
+	return apiErr
+}
+
+// RestoreError is returned by Commit when all commands are committed, but the items of the unarchived
+// sections are not fetched. Do not commit again, but fetch them by ProjectClient.GetData or a full sync.
+type RestoreError struct {
+	SectionIDs []ID
+	Err        error
+}
+
+func (e *RestoreError) Error() string {
+	return fmt.Sprintf("failed to restore items of sections %v: %s", e.SectionIDs, e.Err)
+}
+
+func (e *RestoreError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,63 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestClient_Errors(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.FormValue("label_id") {
+		case "1":
+			w.Write([]byte(`<html>Bad Gateway</html>`))
+		case "2":
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": "Limits reached", "error_code": 35, "error_tag": "LIMITS_REACHED", "http_code": 403}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`Bad Request`))
+		}
+	})
+	ctx := context.Background()
+
+	_, err := client.Label.Get(ctx, "1")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || string(decodeErr.Body) != `<html>Bad Gateway</html>` {
+		t.Errorf("Expect DecodeError, but got %v", err)
+	}
+
+	_, err = client.Label.Get(ctx, "2")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusForbidden || apiErr.Method != http.MethodGet {
+		t.Fatalf("Expect APIError, but got %v", err)
+	}
+	if !errors.Is(err, ErrLimitsReached) {
+		t.Errorf("Expect %s, but got %v", ErrLimitsReached, err)
+	}
+	if strings.Contains(err.Error(), "test-token") || !strings.Contains(err.Error(), "labels/get") {
+		t.Errorf("Unexpect message: %s", err)
+	}
+
+	_, err = client.Label.Get(ctx, "3")
+	if !errors.As(err, &apiErr) || apiErr.Err != nil || string(apiErr.Body) != "Bad Request" {
+		t.Errorf("Expect APIError, but got %v", err)
+	}
+
+	teardown()
+	_, err = client.Label.Get(ctx, "1")
+	var transportErr *TransportError
+	if !errors.As(err, &transportErr) {
+		t.Fatalf("Expect TransportError, but got %v", err)
+	}
+	if strings.Contains(err.Error(), "test-token") {
+		t.Errorf("Expect the token to be redacted, but got %s", err)
+	}
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) || strings.Contains(urlErr.Error(), "test-token") {
+		t.Errorf("Expect the token to be redacted from url.Error, but got %v", urlErr)
+	}
+}
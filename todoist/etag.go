@@ -0,0 +1,129 @@
+package todoist
+
+import (
+	"container/list"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+)
+
+// defaultETagCacheSize is the number of responses kept by WithETagCache unless the size is given.
+const defaultETagCacheSize = 128
+
+// etagEntry is a response encoded again with its ETag, so that callers do not share slices of it.
+type etagEntry struct {
+	key  string
+	etag string
+	body []byte
+}
+
+// etagCache keeps the decoded responses of read-only GETs by the URL and the sync token,
+// and evicts the least recently used one beyond the size.
+type etagCache struct {
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	mu      sync.Mutex
+}
+
+func newETagCache(size int) *etagCache {
+	if size <= 0 {
+		size = defaultETagCacheSize
+	}
+	return &etagCache{size: size, entries: map[string]*list.Element{}, order: list.New()}
+}
+
+func (c *etagCache) get(key string) *etagEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*etagEntry)
+}
+
+func (c *etagCache) put(entry *etagEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[entry.key]; ok {
+		elem.Value = entry
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+	for c.order.Len() > c.size {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(*etagEntry).key)
+	}
+}
+
+func (c *etagCache) len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// etagKey returns the key of the request, which is the URL without the token and the current sync token.
+// A response cached before a sync is not used after it, even if the server says it is not modified.
+func (c *Client) etagKey(req *http.Request) string {
+	u := *req.URL
+	query := u.Query()
+	query.Del("token")
+	u.RawQuery = query.Encode()
+	return u.String() + " " + c.SyncToken()
+}
+
+// doETag sends the GET request with If-None-Match of the cached response, if WithETagCache is given.
+// If the server answers 304 Not Modified, out is set to a copy of the cached response and cached is true.
+// If the cached response is not available, e.g. it is evicted, the request is sent again without If-None-Match.
+func (c *Client) doETag(req *http.Request, out interface{}) (res *http.Response, cached bool, err error) {
+	if c.etags == nil {
+		res, err = c.do(req)
+		return res, false, err
+	}
+	entry := c.etags.get(c.etagKey(req))
+	if entry != nil {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+	res, err = c.do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	if res.StatusCode != http.StatusNotModified {
+		return res, false, nil
+	}
+	res.Body.Close()
+	if entry != nil {
+		v := reflect.New(reflect.TypeOf(out).Elem())
+		if err = json.Unmarshal(entry.body, v.Interface()); err == nil {
+			reflect.ValueOf(out).Elem().Set(v.Elem())
+			return res, true, nil
+		}
+	}
+	req.Header.Del("If-None-Match")
+	res, err = c.do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	return res, false, nil
+}
+
+// storeETag caches out decoded from the response to the request, if the response has ETag.
+func (c *Client) storeETag(req *http.Request, res *http.Response, out interface{}) {
+	if c.etags == nil {
+		return
+	}
+	etag := res.Header.Get("ETag")
+	if len(etag) == 0 {
+		return
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return
+	}
+	c.etags.put(&etagEntry{key: c.etagKey(req), etag: etag, body: b})
+}
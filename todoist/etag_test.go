@@ -0,0 +1,133 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func TestSectionClient_GetETag(t *testing.T) {
+	var requests, decoded int
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		etag := `"v-` + r.URL.Query().Get("section_id") + `"`
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		decoded++
+		w.Header().Set("ETag", etag)
+		w.Write([]byte(`{"section": {"id": ` + r.URL.Query().Get("section_id") + `, "name": "Section"}, "project": {"id": 1, "name": "Project"}}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	client.etags = newETagCache(2)
+
+	for i := 0; i < 2; i++ {
+		res, err := client.Section.Get(context.Background(), "10")
+		if err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		if res.Section.Name != "Section" || res.Project.Name != "Project" {
+			t.Errorf("Unexpect response: %v", res)
+		}
+	}
+	if requests != 2 || decoded != 1 {
+		t.Errorf("Expect 2 requests with 1 response body, but got %d requests with %d", requests, decoded)
+	}
+
+	// the response cached before the sync token changes is not reused.
+	client.SetSyncToken("next-token")
+	if _, err := client.Section.Get(context.Background(), "10"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if decoded != 2 {
+		t.Errorf("Expect %d, but got %d", 2, decoded)
+	}
+
+	for _, id := range []ID{"20", "30", "40"} {
+		if _, err := client.Section.Get(context.Background(), id); err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+	}
+	if client.etags.len() != 2 {
+		t.Errorf("Expect %d, but got %d", 2, client.etags.len())
+	}
+}
+
+func TestItemClient_GetETag(t *testing.T) {
+	var decoded int
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		decoded++
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"item": {"id": 100, "project_id": 1, "content": "Item"}, "project": {"id": 1, "name": "Project"}}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	client.etags = newETagCache(defaultETagCacheSize)
+
+	for i := 0; i < 3; i++ {
+		res, err := client.Item.Get(context.Background(), "100")
+		if err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		if res.Item.Content != "Item" {
+			t.Errorf("Expect %s, but got %s", "Item", res.Item.Content)
+		}
+	}
+	if decoded != 1 {
+		t.Errorf("Expect %d, but got %d", 1, decoded)
+	}
+	if item := client.Item.Resolve("100"); item == nil {
+		t.Error("Expect cached item, but got nil")
+	}
+}
+
+func TestSectionClient_GetETagCopy(t *testing.T) {
+	var requests int
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if len(r.Header.Get("If-None-Match")) != 0 {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		if len(r.FormValue("item_id")) != 0 {
+			w.Write([]byte(`{"item": {"id": 100, "content": "Item", "labels": [1]}, "project": {"id": 1, "name": "Project"}}`))
+			return
+		}
+		w.Write([]byte(`{"section": {"id": 10, "name": "Section"}, "project": {"id": 1, "name": "Project"}}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	client.etags = newETagCache(defaultETagCacheSize)
+
+	item, err := client.Item.Get(context.Background(), "100")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	item.Item.Labels[0] = "2"
+	if item, err = client.Item.Get(context.Background(), "100"); err != nil || item.Item.Labels[0] != "1" {
+		t.Errorf("Expect the cached response not to be modified, but got %v, %v", item, err)
+	}
+	item.Item.Labels[0] = "3"
+	if item, err = client.Item.Get(context.Background(), "100"); err != nil || item.Item.Labels[0] != "1" {
+		t.Errorf("Expect the cached response not to be modified, but got %v, %v", item, err)
+	}
+
+	// the server says not modified, but the cached response is not available.
+	req, _ := client.newRequest(context.Background(), http.MethodGet, pathSectionsGet, url.Values{"section_id": {"10"}})
+	client.etags.put(&etagEntry{key: client.etagKey(req), etag: `"v1"`, body: []byte(`[]`)})
+	requests = 0
+	if res, err := client.Section.Get(context.Background(), "10"); err != nil || res.Section.Name != "Section" {
+		t.Errorf("Expect the response requested again, but got %v, %v", res, err)
+	}
+	if requests != 2 {
+		t.Errorf("Expect %d requests, but got %d", 2, requests)
+	}
+}
@@ -0,0 +1,81 @@
+package todoist
+
+// Favorites is the cached resources marked as favorite.
+type Favorites struct {
+	Projects []Project
+	Labels   []Label
+	Filters  []Filter
+}
+
+// Favorites returns the cached projects, labels and filters marked as favorite, e.g. for a sidebar.
+func (c *Client) Favorites() Favorites {
+	var res Favorites
+	for _, project := range c.Project.GetAll() {
+		if project.IsFavorite.Bool() {
+			res.Projects = append(res.Projects, project)
+		}
+	}
+	for _, label := range c.Label.GetAll() {
+		if label.IsFavorite.Bool() {
+			res.Labels = append(res.Labels, label)
+		}
+	}
+	for _, filter := range c.Filter.GetAll() {
+		if filter.IsFavorite.Bool() {
+			res.Filters = append(res.Filters, filter)
+		}
+	}
+	return res
+}
+
+func favoriteArgs(id ID, favorite bool) map[string]interface{} {
+	return map[string]interface{}{"id": id, "is_favorite": IntBool(favorite)}
+}
+
+// Favorite marks the project as favorite.
+func (c *ProjectClient) Favorite(id ID) error {
+	return c.UpdateFields(id, map[string]interface{}{"is_favorite": IntBool(true)})
+}
+
+// Unfavorite unmarks the project as favorite.
+func (c *ProjectClient) Unfavorite(id ID) error {
+	return c.UpdateFields(id, map[string]interface{}{"is_favorite": IntBool(false)})
+}
+
+// Favorite marks the label as favorite.
+func (c *LabelClient) Favorite(id ID) error {
+	return c.setFavorite(id, true)
+}
+
+// Unfavorite unmarks the label as favorite.
+func (c *LabelClient) Unfavorite(id ID) error {
+	return c.setFavorite(id, false)
+}
+
+func (c *LabelClient) setFavorite(id ID, favorite bool) error {
+	c.enqueue("label_update", favoriteArgs(id, favorite), "")
+	if label := c.cache.resolve(id); label != nil && c.optimistic() {
+		label.IsFavorite = IntBool(favorite)
+		c.cache.store(*label)
+	}
+	return nil
+}
+
+// Favorite marks the filter as favorite.
+func (c *FilterClient) Favorite(id ID) error {
+	return c.setFavorite(id, true)
+}
+
+// Unfavorite unmarks the filter as favorite.
+func (c *FilterClient) Unfavorite(id ID) error {
+	return c.setFavorite(id, false)
+}
+
+func (c *FilterClient) setFavorite(id ID, favorite bool) error {
+	c.enqueue("filter_update", favoriteArgs(id, favorite), "")
+	if filter := c.cache.resolve(id); filter != nil && c.optimistic() {
+		filter.IsFavorite = IntBool(favorite)
+		c.cache.store(*filter)
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClient_Favorites(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	project := Project{Name: "project"}
+	project.ID = "1"
+	client.Project.cache.store(project)
+	label := Label{Name: "label"}
+	label.ID = "2"
+	client.Label.cache.store(label)
+	filter := Filter{Name: "filter", Query: "today"}
+	filter.ID = "3"
+	client.Filter.cache.store(filter)
+
+	client.Project.Favorite("1")
+	client.Label.Favorite("2")
+	client.Filter.Favorite("3")
+	favorites := client.Favorites()
+	if len(favorites.Projects) != 1 || len(favorites.Labels) != 1 || len(favorites.Filters) != 1 {
+		t.Errorf("Unexpect favorites: %v", favorites)
+	}
+	client.Label.Unfavorite("2")
+	if favorites := client.Favorites(); len(favorites.Labels) != 0 {
+		t.Errorf("Expect no favorite labels, but got %v", favorites.Labels)
+	}
+
+	expect := []string{
+		`project_update {"id":"1","is_favorite":true}`,
+		`label_update {"id":"2","is_favorite":true}`,
+		`filter_update {"id":"3","is_favorite":true}`,
+		`label_update {"id":"2","is_favorite":false}`,
+	}
+	pending := client.Pending()
+	if len(pending) != len(expect) {
+		t.Fatalf("Expect %d commands, but got %d", len(expect), len(pending))
+	}
+	for i, command := range pending {
+		b, _ := json.Marshal(command.Args)
+		if s := command.Type + " " + string(b); s != expect[i] {
+			t.Errorf("Expect %s, but got %s", expect[i], s)
+		}
+	}
+}
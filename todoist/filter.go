@@ -3,17 +3,20 @@ package todoist
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/fatih/color"
 	"net/http"
 	"net/url"
 	"strings"
+	"sync"
+	"unicode"
 )
 
 type Filter struct {
 	Entity
 	Name       string  `json:"name"`
 	Query      string  `json:"query"`
-	Color      int     `json:"color"`
+	Color      Color   `json:"color"`
 	ItemOrder  int     `json:"item_order"`
 	IsFavorite IntBool `json:"is_favorite"`
 }
@@ -23,30 +26,11 @@ func (f Filter) String() string {
 }
 
 func (f Filter) ColorString() string {
-	var attr color.Attribute
-	switch f.Color {
-	case 30, 31:
-		attr = color.FgHiRed
-	case 32, 33:
-		attr = color.FgHiYellow
-	case 34, 35, 36:
-		attr = color.FgHiGreen
-	case 37, 38, 39:
-		attr = color.FgHiCyan
-	case 40, 41, 42:
-		attr = color.FgHiBlue
-	case 43, 44, 45, 46:
-		attr = color.FgHiMagenta
-	case 47, 48, 49:
-		attr = color.FgHiBlack
-	default:
-		attr = color.FgWhite
-	}
-	return color.New(attr).Sprint(f.String())
+	return color.New(f.Color.attribute()).Sprint(f.String())
 }
 
 type NewFilterOpts struct {
-	Color      int
+	Color      Color
 	ItemOrder  int
 	IsFavorite IntBool
 }
@@ -55,6 +39,12 @@ func NewFilter(name, query string, opts *NewFilterOpts) (*Filter, error) {
 	if len(name) == 0 || len(query) == 0 {
 		return nil, errors.New("new filter requires a name and a query")
 	}
+	if err := ValidateFilterQuery(query); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &NewFilterOpts{}
+	}
 	filter := Filter{
 		Name:       name,
 		Query:      query,
@@ -63,65 +53,126 @@ func NewFilter(name, query string, opts *NewFilterOpts) (*Filter, error) {
 	}
 	filter.ID = GenerateTempID()
 	if opts.Color == 0 {
-		filter.Color = 47
+		filter.Color = Charcoal
 	} else {
 		filter.Color = opts.Color
 	}
 	return &filter, nil
 }
 
+// ValidateFilterQuery rejects obviously malformed queries, e.g. an empty query,
+// unbalanced parentheses or an operator without an operand.
+// It does not check the filter syntax fully. The server reports the rest on sync.
+func ValidateFilterQuery(query string) error {
+	for _, q := range strings.Split(query, ",") {
+		q = strings.TrimSpace(q)
+		if len(q) == 0 {
+			return fmt.Errorf("invalid filter query %q: empty query", query)
+		}
+		depth := 0
+		// operand reports whether an operand is expected next.
+		operand := true
+		for _, r := range q {
+			switch {
+			case r == '(':
+				depth++
+				operand = true
+			case r == ')':
+				if operand {
+					return fmt.Errorf("invalid filter query %q: missing operand before )", query)
+				}
+				depth--
+				if depth < 0 {
+					return fmt.Errorf("invalid filter query %q: unbalanced parentheses", query)
+				}
+			case r == '&' || r == '|':
+				if operand {
+					return fmt.Errorf("invalid filter query %q: missing operand before %c", query, r)
+				}
+				operand = true
+			case r == '!' || unicode.IsSpace(r):
+			default:
+				operand = false
+			}
+		}
+		if depth != 0 {
+			return fmt.Errorf("invalid filter query %q: unbalanced parentheses", query)
+		}
+		if operand {
+			return fmt.Errorf("invalid filter query %q: missing operand at the end", query)
+		}
+	}
+	return nil
+}
+
 type FilterClient struct {
 	*Client
 	cache *filterCache
 }
 
 func (c *FilterClient) Add(filter Filter) (*Filter, error) {
-	c.cache.store(filter)
-	command := Command{
-		Type:   "filter_add",
-		Args:   filter,
-		UUID:   GenerateUUID(),
-		TempID: filter.ID,
-	}
-	c.queue = append(c.queue, command)
+	if err := ValidateFilterQuery(filter.Query); err != nil {
+		return nil, err
+	}
+	if c.optimistic() {
+		c.cache.store(filter)
+	}
+	c.enqueue("filter_add", filter, filter.ID)
 	return &filter, nil
 }
 
 func (c *FilterClient) Update(filter Filter) (*Filter, error) {
-	command := Command{
-		Type: "filter_update",
-		Args: filter,
-		UUID: GenerateUUID(),
+	if err := validateName("filter", filter.Name); err != nil {
+		return nil, err
+	}
+	if err := ValidateFilterQuery(filter.Query); err != nil {
+		return nil, err
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue("filter_update", filter, "")
 	return &filter, nil
 }
 
 func (c *FilterClient) Delete(id ID) error {
-	command := Command{
-		Type: "filter_delete",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("filter_delete", map[string]ID{"id": id}, "")
 	return nil
 }
 
+// DeleteIfExists deletes the filter only if it is cached. See SectionClient.DeleteIfExists.
+func (c *FilterClient) DeleteIfExists(id ID, opts *DeleteOpts) error {
+	if c.Resolve(id) == nil {
+		return opts.notFound("filter", id)
+	}
+	return c.Delete(id)
+}
+
 func (c *FilterClient) UpdateOrders(filters []Filter) error {
 	args := map[ID]int{}
 	for _, filter := range filters {
 		args[filter.ID] = filter.ItemOrder
 	}
-	command := Command{
-		Type: "filter_update_orders",
-		UUID: GenerateUUID(),
-		Args: map[string]map[ID]int{
-			"id_order_mapping": args,
-		},
+	c.enqueue("filter_update_orders", map[string]map[ID]int{"id_order_mapping": args}, "")
+	return nil
+}
+
+// Reorder orders the filters along the given ids, numbering item orders from 1.
+func (c *FilterClient) Reorder(ids []ID) error {
+	if len(ids) == 0 {
+		return errors.New("reorder requires filters")
+	}
+	filters := make([]Filter, len(ids))
+	for i, id := range ids {
+		filters[i].ID = id
+		filters[i].ItemOrder = i + 1
+	}
+	if err := c.UpdateOrders(filters); err != nil {
+		return err
+	}
+	for _, f := range filters {
+		if filter := c.Resolve(f.ID); filter != nil && c.optimistic() {
+			filter.ItemOrder = f.ItemOrder
+			c.cache.store(*filter)
+		}
 	}
-	c.queue = append(c.queue, command)
 	return nil
 }
 
@@ -131,16 +182,16 @@ type FilterGetResponse struct {
 
 func (c *FilterClient) Get(ctx context.Context, id ID) (*FilterGetResponse, error) {
 	values := url.Values{"filter_id": {id.String()}}
-	req, err := c.newRequest(ctx, http.MethodGet, "filters/get", values)
+	req, err := c.newRequest(ctx, http.MethodGet, pathFiltersGet, values)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	var out FilterGetResponse
-	err = decodeBody(res, &out)
+	err = c.decodeBody(res, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -151,6 +202,7 @@ func (c *FilterClient) GetAll() []Filter {
 	return c.cache.getAll()
 }
 
+// Resolve returns a copy of the cached filter. Use Update to modify it.
 func (c *FilterClient) Resolve(id ID) *Filter {
 	return c.cache.resolve(id)
 }
@@ -167,46 +219,152 @@ func (c FilterClient) FindByName(substr string) []Filter {
 
 type filterCache struct {
 	cache *[]Filter
+	// index maps ids to positions in cache.
+	index  map[ID]int
+	mu     sync.RWMutex
+	notify *notifier
 }
 
 func (c *filterCache) getAll() []Filter {
-	return *c.cache
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Filter, len(*c.cache))
+	copy(res, *c.cache)
+	return res
 }
 
+// resolve returns a copy of the cached filter, so that it is safe to read
+// while the cache is updated. Modifying it does not affect the cache.
 func (c *filterCache) resolve(id ID) *Filter {
-	for _, filter := range *c.cache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	i, ok := c.lookup(id)
+	if !ok {
+		return nil
+	}
+	res := (*c.cache)[i]
+	return &res
+}
+
+// lookup returns the position of the filter in the cache. The caller must hold the lock.
+// The cache is scanned if it is not indexed yet.
+func (c *filterCache) lookup(id ID) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[id]
+		return i, ok
+	}
+	for i, filter := range *c.cache {
 		if filter.ID == id {
-			return &filter
+			return i, true
 		}
 	}
-	return nil
+	return 0, false
+}
+
+// set swaps the cache for the filters and indexes them. The caller must hold the lock.
+func (c *filterCache) set(filters []Filter) {
+	c.cache = &filters
+	c.index = indexFilters(filters)
+}
+
+func indexFilters(filters []Filter) map[ID]int {
+	res := make(map[ID]int, len(filters))
+	for i, filter := range filters {
+		res[filter.ID] = i
+	}
+	return res
 }
 
 func (c *filterCache) store(filter Filter) {
-	var res []Filter
-	isNew := true
-	for _, f := range *c.cache {
-		if f.Equal(filter) {
-			if !filter.IsDeleted {
-				res = append(res, filter)
-			}
-			isNew = false
-		} else {
-			res = append(res, f)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("filters")
+	i, ok := c.lookup(filter.ID)
+	switch {
+	case filter.IsDeleted.Bool():
+		if ok {
+			c.removeAt(i)
+		}
+	case ok:
+		(*c.cache)[i] = filter
+	default:
+		*c.cache = append(*c.cache, filter)
+		if c.index != nil {
+			c.index[filter.ID] = len(*c.cache) - 1
 		}
 	}
-	if isNew && !filter.IsDeleted.Bool() {
-		res = append(res, filter)
-	}
-	c.cache = &res
 }
 
 func (c *filterCache) remove(filter Filter) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("filters")
+	if i, ok := c.lookup(filter.ID); ok {
+		c.removeAt(i)
+	}
+}
+
+// removeAt removes the filter at the position, and shifts the positions of the following ones in the index.
+// The caller must hold the lock.
+func (c *filterCache) removeAt(i int) {
+	filters := *c.cache
+	id := filters[i].ID
+	copy(filters[i:], filters[i+1:])
+	filters = filters[:len(filters)-1]
+	*c.cache = filters
+	if c.index != nil {
+		delete(c.index, id)
+		for j := i; j < len(filters); j++ {
+			c.index[filters[j].ID] = j
+		}
+	}
+}
+
+func (c *filterCache) replaceTempIDs(mapping map[ID]ID) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("filters")
+	res := make([]Filter, len(*c.cache))
+	copy(res, *c.cache)
+	for i, filter := range res {
+		if id, ok := mapping[filter.ID]; ok {
+			res[i].ID = id
+		}
+	}
+	c.set(res)
+}
+
+// replace swaps the cache for the given filters at once.
+// Cached filters with temporary ids are kept, since they are not committed yet.
+func (c *filterCache) replace(filters []Filter) {
 	var res []Filter
-	for _, f := range *c.cache {
-		if !f.Equal(filter) {
-			res = append(res, f)
+	for _, filter := range filters {
+		if !filter.IsDeleted.Bool() {
+			res = append(res, filter)
+		}
+	}
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("filters")
+	for _, filter := range *c.cache {
+		if IsTempID(filter.ID) {
+			res = append(res, filter)
 		}
 	}
-	c.cache = &res
+	c.set(res)
+}
+
+// reset sets the cache to the given filters, dropping local changes.
+func (c *filterCache) reset(filters []Filter) {
+	res := make([]Filter, len(filters))
+	copy(res, filters)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("filters")
+	c.set(res)
 }
@@ -0,0 +1,46 @@
+package todoist
+
+import "testing"
+
+func TestValidateFilterQuery(t *testing.T) {
+	for _, query := range []string{
+		"today",
+		"today | overdue",
+		"(today | overdue) & #Work",
+		"!assigned & p1, 7 days",
+		"search: meeting (notes)",
+	} {
+		if err := ValidateFilterQuery(query); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+	}
+	for _, query := range []string{
+		"",
+		"  ",
+		"today,",
+		"(today | overdue",
+		"today)",
+		"today &",
+		"| today",
+		"()",
+	} {
+		if err := ValidateFilterQuery(query); err == nil {
+			t.Errorf("Expect error for %q, but got nil", query)
+		}
+	}
+}
+
+func TestFilterClient_Add(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+
+	if _, err := client.Filter.Add(Filter{Name: "broken", Query: "(today"}); err == nil {
+		t.Error("Expect error for invalid query, but got nil")
+	}
+	if len(client.queue) != 0 || len(client.Filter.GetAll()) != 0 {
+		t.Error("Expect invalid filter not to be queued")
+	}
+	if _, err := NewFilter("broken", "today &", &NewFilterOpts{}); err == nil {
+		t.Error("Expect error for invalid query, but got nil")
+	}
+}
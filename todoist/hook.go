@@ -0,0 +1,104 @@
+package todoist
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// RequestInfo describes a request sent by the client and its response.
+// The API token is redacted from URL, Header and Err.
+type RequestInfo struct {
+	Method string
+	URL    string
+	Header http.Header
+	// Commands is the number of commands sent by a sync request.
+	Commands int
+	// StatusCode is 0 if no response is received.
+	StatusCode int
+	Duration   time.Duration
+	// Attempt is the number of the attempt starting from 1, which is greater on retries.
+	Attempt int
+	Err     error
+}
+
+// RequestHook is called after each request is sent, e.g. to log it. See WithRequestHook.
+type RequestHook func(info RequestInfo)
+
+// observe passes the request to the hook if any.
+func (c *Client) observe(req *http.Request, res *http.Response, err error, start time.Time, attempt int) {
+	if c.requestHook == nil {
+		return
+	}
+	header := req.Header.Clone()
+	if len(header.Get("Authorization")) != 0 {
+		header.Set("Authorization", redacted)
+	}
+	info := RequestInfo{
+		Method:   req.Method,
+		URL:      redactURL(req.URL),
+		Header:   header,
+		Commands: countCommands(req),
+		Duration: time.Since(start),
+		Attempt:  attempt,
+		Err:      redactError(err),
+	}
+	if res != nil {
+		info.StatusCode = res.StatusCode
+	}
+	c.requestHook(info)
+}
+
+// countCommands returns the number of commands in the body of a sync request.
+func countCommands(req *http.Request) int {
+	if req.GetBody == nil {
+		return 0
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return 0
+	}
+	defer body.Close()
+	b, err := ioutil.ReadAll(body)
+	if err != nil {
+		return 0
+	}
+	values, err := url.ParseQuery(string(b))
+	if err != nil || len(values.Get("commands")) == 0 {
+		return 0
+	}
+	var commands []json.RawMessage
+	if err = json.Unmarshal([]byte(values.Get("commands")), &commands); err != nil {
+		return 0
+	}
+	return len(commands)
+}
+
+// redactURL returns the URL with the API token redacted.
+func redactURL(u *url.URL) string {
+	res := *u
+	if q := res.Query(); len(q.Get("token")) != 0 {
+		q.Set("token", redacted)
+		res.RawQuery = q.Encode()
+	}
+	return res.String()
+}
+
+// redactError returns a copy of *url.Error with the API token redacted from its URL, or err as is.
+func redactError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+	res := *urlErr
+	if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+		res.URL = redactURL(u)
+	} else {
+		res.URL = redacted
+	}
+	return &res
+}
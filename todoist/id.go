@@ -46,16 +46,12 @@ func (i ID) String() string {
 	return string(i)
 }
 
+// MarshalJSON marshals the id as a string as the API v9 does, or null for the zero id.
 func (i ID) MarshalJSON() ([]byte, error) {
-	s := string(i)
-	if IsTempID(i) {
-		s = `"` + s + `"`
-	}
 	if i.IsZero() {
-		s = "null"
-
+		return []byte("null"), nil
 	}
-	return []byte(s), nil
+	return []byte(strconv.Quote(string(i))), nil
 }
 
 func (i *ID) UnmarshalJSON(b []byte) (err error) {
@@ -43,7 +43,7 @@ func TestNewID(t *testing.T) {
 func TestID_MarshalJSON(t *testing.T) {
 	test := testIDs[0]
 	b, err := test.v.MarshalJSON()
-	if err != nil || string(b) != test.s {
+	if err != nil || string(b) != strconv.Quote(test.s) {
 		t.Errorf("Expect %s, but got %s", strconv.Quote(test.s), string(b))
 	}
 
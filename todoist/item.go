@@ -3,37 +3,47 @@ package todoist
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
 type Item struct {
 	Entity
-	UserID         ID      `json:"user_id,omitempty"`
-	ProjectID      ID      `json:"project_id,omitempty"`
-	Content        string  `json:"content"`
-	Due            Due     `json:"due,omitempty"`
-	Priority       int     `json:"priority,omitempty"`
-	ParentID       ID      `json:"parent_id,omitempty"`
-	ChildOrder     int     `json:"child_order,omitempty"`
-	DayOrder       int     `json:"day_order,omitempty"`
-	Collapsed      IntBool `json:"collapsed,omitempty"`
-	Labels         []ID    `json:"labels,omitempty"`
-	AssignedByUID  ID      `json:"assigned_by_uid,omitempty"`
-	ResponsibleUID ID      `json:"responsible_uid,omitempty"`
-	Checked        IntBool `json:"checked,omitempty"`
-	InHistory      IntBool `json:"in_history,omitempty"`
-	SyncID         int     `json:"sync_id,omitempty"`
-	DateAdded      Time    `json:"date_added,omitempty"`
-	CompletedDate  Time    `json:"completed_date"`
+	UserID         ID        `json:"user_id,omitempty"`
+	ProjectID      ID        `json:"project_id,omitempty"`
+	SectionID      ID        `json:"section_id,omitempty"`
+	Content        string    `json:"content"`
+	Due            Due       `json:"due,omitempty"`
+	Deadline       *Deadline `json:"deadline,omitempty"`
+	Duration       *Duration `json:"duration,omitempty"`
+	Priority       Priority  `json:"priority,omitempty"`
+	ParentID       ID        `json:"parent_id,omitempty"`
+	ChildOrder     int       `json:"child_order,omitempty"`
+	DayOrder       int       `json:"day_order,omitempty"`
+	Collapsed      IntBool   `json:"collapsed,omitempty"`
+	Labels         []ID      `json:"labels,omitempty"`
+	AssignedByUID  ID        `json:"assigned_by_uid,omitempty"`
+	ResponsibleUID ID        `json:"responsible_uid,omitempty"`
+	Checked        IntBool   `json:"checked,omitempty"`
+	InHistory      IntBool   `json:"in_history,omitempty"`
+	SyncID         int       `json:"sync_id,omitempty"`
+	DateAdded      Time      `json:"added_at,omitempty"`
+	CompletedDate  Time      `json:"completed_at"`
+	// TaskID is the id of the live item, set only in the items of CompletedItems.
+	TaskID ID `json:"task_id,omitempty"`
 }
 
 type NewItemOpts struct {
 	ProjectID       ID
+	SectionID       ID
 	Due             Due
-	Priority        int
+	Duration        *Duration
+	Priority        Priority
 	ParentID        ID
 	ChildOrder      int
 	DayOrder        int
@@ -49,10 +59,15 @@ func NewItem(content string, opts *NewItemOpts) (*Item, error) {
 	if len(content) == 0 {
 		return nil, errors.New("new item requires a content")
 	}
+	if opts == nil {
+		opts = &NewItemOpts{}
+	}
 	item := Item{
 		ProjectID:      opts.ProjectID,
+		SectionID:      opts.SectionID,
 		Content:        content,
 		Due:            opts.Due,
+		Duration:       opts.Duration,
 		ParentID:       opts.ParentID,
 		ChildOrder:     opts.ChildOrder,
 		DayOrder:       opts.DayOrder,
@@ -63,7 +78,7 @@ func NewItem(content string, opts *NewItemOpts) (*Item, error) {
 	}
 	item.ID = GenerateTempID()
 	if opts.Priority == 0 {
-		item.Priority = 1
+		item.Priority = P4
 	} else {
 		item.Priority = opts.Priority
 	}
@@ -74,6 +89,27 @@ func (i Item) IsOverDueDate() bool {
 	return i.Due.Date.Before(Time{time.Now().UTC()})
 }
 
+// DueTime returns the due date of the item, and false if the item has no due date.
+func (i Item) DueTime() (time.Time, bool) {
+	if i.Due.Date.IsZero() {
+		return time.Time{}, false
+	}
+	return i.Due.Date.Time, true
+}
+
+// IsOverdue reports whether the due of the item is passed at now.
+// An all-day due is overdue after the end of the day.
+func (i Item) IsOverdue(now time.Time) bool {
+	t, ok := i.DueTime()
+	if !ok {
+		return false
+	}
+	if i.Due.IsFullDay() {
+		return !now.Before(t.AddDate(0, 0, 1))
+	}
+	return t.Before(now)
+}
+
 func (i Item) IsChecked() bool {
 	return i.Checked.Bool()
 }
@@ -84,152 +120,626 @@ type ItemClient struct {
 }
 
 func (c *ItemClient) Add(item Item) (*Item, error) {
-	// TODO: support auto_reminder and auto_parse_labels
+	// TODO: support auto_parse_labels
 	// append item to sync state only `add` method?
-	c.cache.store(item)
-	command := Command{
-		Type:   "item_add",
-		Args:   item,
-		UUID:   GenerateUUID(),
-		TempID: item.ID,
-	}
-	c.queue = append(c.queue, command)
+	if c.optimistic() {
+		c.cache.store(item)
+	}
+	c.enqueue("item_add", item, item.ID)
 	return &item, nil
 }
 
-func (c *ItemClient) Update(item Item) (*Item, error) {
-	command := Command{
-		Type: "item_update",
-		Args: item,
-		UUID: GenerateUUID(),
+// ItemAddOpts configures AddWithOpts.
+type ItemAddOpts struct {
+	// WithAutoReminder adds a relative reminder by AutoReminderMinutes
+	// if the item has a due time, as the official apps do.
+	WithAutoReminder bool
+}
+
+// AddWithOpts adds the item as Add does, along with the reminder by opts. nil opts is the same as Add.
+// The reminder requires the user to be synced. An item without an id is given a temporary id,
+// so that the reminder refers to it.
+func (c *ItemClient) AddWithOpts(item Item, opts *ItemAddOpts) (*Item, error) {
+	if opts == nil {
+		opts = &ItemAddOpts{}
+	}
+	if item.ID.IsZero() {
+		item.ID = GenerateTempID()
 	}
-	c.queue = append(c.queue, command)
+	var reminder *Reminder
+	if opts.WithAutoReminder && !item.Due.Date.IsZero() && !item.Due.IsFullDay() {
+		minutes := c.Reminder.AutoReminderMinutes()
+		if minutes < 0 {
+			return nil, errors.New("auto reminder requires the user settings, sync first")
+		}
+		var err error
+		if reminder, err = NewRelativeReminder(item.ID, minutes, &NewReminderOpts{}); err != nil {
+			return nil, err
+		}
+	}
+	res, err := c.Add(item)
+	if err != nil {
+		return nil, err
+	}
+	if reminder != nil {
+		if _, err = c.Reminder.Add(*reminder); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// AddWithReminder adds the item with a due time, and a reminder minutesBefore minutes before the due.
+// Both are queued in order, so that the reminder refers to the item by its temporary id in the same commit.
+// It returns the temporary ids of the item and the reminder.
+func (c *ItemClient) AddWithReminder(item Item, minutesBefore int) (ID, ID, error) {
+	if item.Due.Date.IsZero() || item.Due.IsFullDay() {
+		return "", "", errors.New("add with reminder requires a due time")
+	}
+	if item.ID.IsZero() {
+		item.ID = GenerateTempID()
+	}
+	reminder, err := NewRelativeReminder(item.ID, minutesBefore, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err = c.Add(item); err != nil {
+		return "", "", err
+	}
+	if _, err = c.Reminder.Add(*reminder); err != nil {
+		return "", "", err
+	}
+	return item.ID, reminder.ID, nil
+}
+
+func (c *ItemClient) Update(item Item) (*Item, error) {
+	c.enqueue("item_update", item, "")
 	return &item, nil
 }
 
+// itemUpdateFields are the fields UpdateFields accepts. Use Move to change project_id, section_id or parent_id.
+var itemUpdateFields = []string{"content", "due", "deadline", "duration", "priority", "collapsed", "labels", "day_order", "assigned_by_uid", "responsible_uid"}
+
+// UpdateFields updates only the given fields of the item, e.g. {"content": "Buy milk"},
+// so that the other fields are not cleared by zero values. Accepted fields are
+// "content", "due" (Due), "deadline" (*Deadline), "duration" (*Duration), "priority" (Priority),
+// "collapsed" (IntBool), "labels" ([]ID), "day_order", "assigned_by_uid" and "responsible_uid" (ID).
+// The cached item is rolled back if the command fails on Commit.
+func (c *ItemClient) UpdateFields(id ID, fields map[string]interface{}) error {
+	args, err := updateArgs(id, fields, itemUpdateFields)
+	if err != nil {
+		return err
+	}
+	if item := c.Resolve(id); item != nil && c.optimistic() {
+		if err = mergeFields(item, fields); err != nil {
+			return err
+		}
+		c.cache.markPending(id)
+		c.cache.store(*item)
+	}
+	c.enqueue("item_update", args, "")
+	return nil
+}
+
+// SetCollapsed collapses or expands the subtasks of the item, as the official apps show them.
+func (c *ItemClient) SetCollapsed(id ID, collapsed bool) error {
+	return c.UpdateFields(id, map[string]interface{}{"collapsed": IntBool(collapsed)})
+}
+
 func (c *ItemClient) Delete(id ID) error {
-	command := Command{
-		Type: "item_delete",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("item_delete", map[string]ID{"id": id}, "")
 	return nil
 }
 
+// DeleteIfExists deletes the item only if it is cached. See SectionClient.DeleteIfExists.
+func (c *ItemClient) DeleteIfExists(id ID, opts *DeleteOpts) error {
+	if c.Resolve(id) == nil {
+		return opts.notFound("item", id)
+	}
+	return c.Delete(id)
+}
+
+// ItemMoveOpts specifies the destination of Move. Exactly one of them must be set.
 type ItemMoveOpts struct {
 	ParentID  ID
 	ProjectID ID
+	SectionID ID
 }
 
+var errRequireMoveTarget = errors.New("require parent item id, project id or section id")
+
+// Move moves the item under a parent item, to a project or to a section.
+// It returns an error if opts is nil or sets none of them.
+// The cached item and its subtasks are moved as well, so that GetAll reflects it before the next sync,
+// and they are rolled back if the command fails on Commit.
 func (c *ItemClient) Move(id ID, opts *ItemMoveOpts) error {
-	switch len(opts.ParentID) + len(opts.ProjectID) {
-	case 0:
-		return errors.New("require parent item id or project id")
-	case 2:
-		return errors.New("require either parent item id or project id")
+	if opts == nil {
+		return errRequireMoveTarget
 	}
 	args := map[string]interface{}{
 		"id": id,
 	}
-	if len(opts.ParentID) != 0 {
+	if !opts.ParentID.IsZero() {
 		args["parent_id"] = opts.ParentID
 	}
-	if len(opts.ProjectID) != 0 {
+	if !opts.ProjectID.IsZero() {
 		args["project_id"] = opts.ProjectID
 	}
+	if !opts.SectionID.IsZero() {
+		args["section_id"] = opts.SectionID
+	}
+	switch len(args) {
+	case 1:
+		return errRequireMoveTarget
+	case 2:
+	default:
+		return errors.New("require only one of parent item id, project id or section id")
+	}
 
-	command := Command{
-		Type: "item_move",
-		UUID: GenerateUUID(),
-		Args: args,
+	c.enqueue("item_move", args, "")
+
+	if item := c.Resolve(id); item != nil && c.optimistic() {
+		switch {
+		case !opts.ParentID.IsZero():
+			item.ParentID = opts.ParentID
+			if parent := c.Resolve(opts.ParentID); parent != nil {
+				item.ProjectID = parent.ProjectID
+				item.SectionID = parent.SectionID
+			}
+		case !opts.ProjectID.IsZero():
+			item.ProjectID = opts.ProjectID
+			item.SectionID = ""
+			item.ParentID = ""
+		case !opts.SectionID.IsZero():
+			item.SectionID = opts.SectionID
+			item.ParentID = ""
+			if section := c.Section.Resolve(opts.SectionID); section != nil {
+				item.ProjectID = section.ProjectID
+			}
+		}
+		descendants := c.Descendants(id)
+		var linked []ID
+		for _, descendant := range descendants {
+			linked = append(linked, descendant.ID)
+		}
+		c.cache.markPending(id, linked...)
+		c.cache.store(*item)
+		// the subtasks follow the item to its project and section.
+		for _, descendant := range descendants {
+			descendant.ProjectID = item.ProjectID
+			descendant.SectionID = item.SectionID
+			c.cache.store(descendant)
+		}
 	}
-	c.queue = append(c.queue, command)
 	return nil
 }
 
+// Complete completes the item.
+// A cached recurring item is closed instead, so that the server advances it to the next occurrence
+// rather than completing it for good. It stays in the cache and gets the next due date by the sync of Commit.
+// A non-recurring item is marked as checked in the cache.
 func (c *ItemClient) Complete(id ID, dateCompleted Time, forceHistory bool) error {
+	item := c.Resolve(id)
+	if item != nil && item.Due.IsRecurring {
+		return c.Close(id)
+	}
 	var fh int
 	if forceHistory {
 		fh = 1
 	} else {
 		fh = 0
 	}
-	command := Command{
-		Type: "item_complete",
-		UUID: GenerateUUID(),
-		Args: map[string]interface{}{
-			"id":             id,
-			"date_completed": dateCompleted,
-			"force_history":  fh,
-		},
+	c.enqueue("item_complete", map[string]interface{}{
+		"id":             id,
+		"date_completed": dateCompleted,
+		"force_history":  fh,
+	}, "")
+	if item != nil && c.optimistic() {
+		item.Checked = true
+		c.cache.store(*item)
 	}
-	c.queue = append(c.queue, command)
 	return nil
 }
 
+// Uncomplete reopens the completed item and marks it as unchecked in the cache.
 func (c *ItemClient) Uncomplete(id ID) error {
-	command := Command{
-		Type: "item_uncomplete",
-		UUID: GenerateUUID(),
-		Args: map[string]interface{}{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("item_uncomplete", map[string]interface{}{
+		"id": id,
+	}, "")
+	if item := c.Resolve(id); item != nil && c.optimistic() {
+		item.Checked = false
+		c.cache.store(*item)
+	}
+	return nil
+}
+
+// UncompleteByTaskID reopens the completed item by the task id of an item in CompletedItems.
+// The item is not in the cache while it is completed. It is pulled into the cache by the sync of Commit,
+// since the server reports the reopened item as changed, unless WithResourceTypes excludes items.
+func (c *ItemClient) UncompleteByTaskID(taskID ID) error {
+	if taskID.IsZero() {
+		return errors.New("uncomplete requires a task id")
+	}
+	return c.Uncomplete(taskID)
+}
+
+// AddLabel adds the label to the cached item. The server replaces the labels of the item
+// rather than patching them, so the command has all of the labels.
+// Labels are given by ids, and names are normalized to the ids of the cached labels.
+// Nothing is queued if the item has the label already.
+func (c *ItemClient) AddLabel(id, labelID ID) error {
+	return c.updateLabels(id, labelID, true)
+}
+
+// RemoveLabel removes the label from the cached item as AddLabel does.
+// Nothing is queued if the item does not have the label.
+func (c *ItemClient) RemoveLabel(id, labelID ID) error {
+	return c.updateLabels(id, labelID, false)
+}
+
+func (c *ItemClient) updateLabels(id, labelID ID, add bool) error {
+	if labelID.IsZero() {
+		return errors.New("update labels requires a label")
+	}
+	item := c.Resolve(id)
+	if item == nil {
+		return &NotFoundError{Resource: "item", ID: id}
+	}
+	labelID = c.normalizeLabel(labelID)
+	labels := []ID{}
+	found := false
+	for _, l := range item.Labels {
+		l = c.normalizeLabel(l)
+		if l == labelID {
+			found = true
+			if !add {
+				continue
+			}
+		}
+		labels = append(labels, l)
+	}
+	if found == add {
+		return nil
+	}
+	if add {
+		labels = append(labels, labelID)
+	}
+	c.enqueue("item_update", map[string]interface{}{
+		"id":     id,
+		"labels": labels,
+	}, "")
+	if c.optimistic() {
+		item.Labels = labels
+		c.cache.store(*item)
+	}
+	return nil
+}
+
+// normalizeLabel returns the id of the cached label named by the given value, with or without "@",
+// since some versions of the API refer to labels by names. Other values are returned as is.
+func (c *ItemClient) normalizeLabel(v ID) ID {
+	if c.Label.Resolve(v) != nil {
+		return v
+	}
+	name := strings.TrimPrefix(v.String(), "@")
+	for _, label := range c.Label.GetAll() {
+		if label.Name == name {
+			return label.ID
+		}
+	}
+	return v
+}
+
+// GetByLabel returns the cached items with the label, ordered by child order.
+// Labels of the items are matched by ids or by names as AddLabel does.
+// It returns an empty slice if no item has the label.
+func (c *ItemClient) GetByLabel(labelID ID) []Item {
+	res := []Item{}
+	if labelID.IsZero() {
+		return res
+	}
+	labelID = c.normalizeLabel(labelID)
+	// the names of the label, to match the items referring to it by names.
+	names := map[ID]bool{labelID: true}
+	if label := c.Label.Resolve(labelID); label != nil {
+		names[ID(label.Name)] = true
+		names[ID("@"+label.Name)] = true
+	}
+	for _, item := range c.GetAll() {
+		for _, l := range item.Labels {
+			if names[l] {
+				res = append(res, item)
+				break
+			}
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].ChildOrder < res[j].ChildOrder
+	})
+	return res
+}
+
+// GetByLabelName returns the cached items with the label of the name, with or without "@", as GetByLabel does.
+func (c *ItemClient) GetByLabelName(name string) []Item {
+	return c.GetByLabel(ID(strings.TrimPrefix(name, "@")))
+}
+
+// DeadlineDate returns the date of the deadline, and false if the item has no deadline.
+func (i Item) DeadlineDate() (time.Time, bool) {
+	if i.Deadline == nil || i.Deadline.Date.IsZero() {
+		return time.Time{}, false
+	}
+	return i.Deadline.Date.Time, true
+}
+
+// SetDeadline sets the deadline of the item to the date of t. A zero t clears the deadline.
+func (c *ItemClient) SetDeadline(id ID, t time.Time) error {
+	var deadline *Deadline
+	if !t.IsZero() {
+		d := NewDeadline(t)
+		deadline = &d
+	}
+	c.enqueue("item_update", map[string]interface{}{
+		"id":       id,
+		"deadline": deadline,
+	}, "")
+	if item := c.Resolve(id); item != nil && c.optimistic() {
+		item.Deadline = deadline
+		c.cache.store(*item)
+	}
 	return nil
 }
 
+// DurationMinutes returns the duration of the item in minutes, and false if the item has no duration.
+func (i Item) DurationMinutes() (int, bool) {
+	if i.Duration == nil {
+		return 0, false
+	}
+	return i.Duration.Minutes(), true
+}
+
+// SetDuration sets the duration of the item in minutes. Zero or less clears the duration.
+func (c *ItemClient) SetDuration(id ID, minutes int) error {
+	var duration *Duration
+	if minutes > 0 {
+		duration = &Duration{Amount: minutes, Unit: DurationUnitMinute}
+	}
+	c.enqueue("item_update", map[string]interface{}{
+		"id":       id,
+		"duration": duration,
+	}, "")
+	if item := c.Resolve(id); item != nil && c.optimistic() {
+		item.Duration = duration
+		c.cache.store(*item)
+	}
+	return nil
+}
+
+// CompleteMany queues the completion of the items as Complete does, and returns the number of queued commands.
+// Commit sends them in chunks of the request limit, so that any number of items can be completed at once.
+func (c *ItemClient) CompleteMany(ids []ID) (int, error) {
+	for i, id := range ids {
+		if err := c.Complete(id, Time{}, false); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// DeleteMany queues the deletion of the items, and returns the number of queued commands.
+func (c *ItemClient) DeleteMany(ids []ID) (int, error) {
+	for i, id := range ids {
+		if err := c.Delete(id); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// MoveMany queues the move of the items to the same destination, and returns the number of queued commands.
+// Nothing is queued if opts is invalid.
+func (c *ItemClient) MoveMany(ids []ID, opts *ItemMoveOpts) (int, error) {
+	if opts == nil {
+		return 0, errRequireMoveTarget
+	}
+	for i, id := range ids {
+		if err := c.Move(id, opts); err != nil {
+			return i, err
+		}
+	}
+	return len(ids), nil
+}
+
+// Close does what the check of the official apps does.
+// It completes a non-recurring item, and advances a recurring item to the next occurrence.
 func (c *ItemClient) Close(id ID) error {
-	command := Command{
-		Type: "item_close",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("item_close", map[string]ID{"id": id}, "")
 	return nil
 }
 
-type ItemGetResponse struct {
-	Item    Item
-	Project Project
-	Notes   []Note
+// ItemOrder is a pair of an item and its order among the siblings.
+type ItemOrder struct {
+	ID         ID  `json:"id"`
+	ChildOrder int `json:"child_order"`
 }
 
-func (c *ItemClient) Get(ctx context.Context, id ID) (*ItemGetResponse, error) {
-	values := url.Values{"item_id": {id.String()}}
-	req, err := c.newRequest(ctx, http.MethodGet, "items/get", values)
+// Reorder updates the orders of the sibling items, and the child orders of the cached items.
+// GetAll returns the reordered items in the new order, in the positions they had.
+func (c *ItemClient) Reorder(items []ItemOrder) error {
+	if len(items) == 0 {
+		return errors.New("reorder requires items")
+	}
+	c.enqueue("item_reorder", map[string][]ItemOrder{"items": items}, "")
+	if c.optimistic() {
+		orders := map[ID]int{}
+		for _, order := range items {
+			orders[order.ID] = order.ChildOrder
+		}
+		c.cache.reorder(orders)
+	}
+	return nil
+}
+
+// ItemDayOrder is a pair of an item and its order in the Today and upcoming views.
+type ItemDayOrder struct {
+	ID       ID
+	DayOrder int
+}
+
+// ReorderDay updates the orders of the items in the Today and upcoming views, and the day orders of the cached items.
+// The orders are sent with DayOrdersTimestamp, so that Commit fails with *ConflictError
+// if the day orders are changed by another client since the last sync. The rejected orders are not retried
+// by the next Commit. Sync and reorder again then.
+func (c *ItemClient) ReorderDay(items []ItemDayOrder) error {
+	if len(items) == 0 {
+		return errors.New("reorder day requires items")
+	}
+	orders := map[ID]int{}
+	for _, order := range items {
+		orders[order.ID] = order.DayOrder
+	}
+	args := map[string]interface{}{"ids_to_orders": orders}
+	if timestamp := c.DayOrdersTimestamp(); len(timestamp) != 0 {
+		args["day_orders_timestamp"] = timestamp
+	}
+	c.enqueue("item_update_day_orders", args, "")
+	for _, order := range items {
+		if item := c.Resolve(order.ID); item != nil && c.optimistic() {
+			item.DayOrder = order.DayOrder
+			c.cache.store(*item)
+		}
+	}
+	return nil
+}
+
+// GetTodayOrdered returns the unchecked cached items due on the day of now, ordered by day order.
+// The day is of the user's timezone if the user is synced, or of the location of now.
+func (c *ItemClient) GetTodayOrdered(now time.Time) []Item {
+	now = c.userNow(now)
+	loc := now.Location()
+	today := now.Format(dateLayout)
+	var res []Item
+	for _, item := range c.GetAll() {
+		if item.IsChecked() || item.Due.Date.IsZero() {
+			continue
+		}
+		date := item.Due.Date.Time
+		// dates without timezone are the wall clock of the user.
+		if len(item.Due.Timezone) != 0 {
+			date = date.In(loc)
+		}
+		if date.Format(dateLayout) == today {
+			res = append(res, item)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].DayOrder < res[j].DayOrder
+	})
+	return res
+}
+
+// ReorderIDs reorders the sibling items along the given ids, numbering child orders from 1.
+func (c *ItemClient) ReorderIDs(ids []ID) error {
+	items := make([]ItemOrder, len(ids))
+	for i, id := range ids {
+		items[i] = ItemOrder{ID: id, ChildOrder: i + 1}
+	}
+	return c.Reorder(items)
+}
+
+// QuickAdd adds an item from the text parsed like the quick add of the official apps.
+// e.g. "Buy milk tomorrow at 5pm #Errands @shopping p1"
+// The item is added immediately, not via the command queue.
+func (c *ItemClient) QuickAdd(ctx context.Context, text string) (*Item, error) {
+	if len(text) == 0 {
+		return nil, errors.New("quick add requires a text")
+	}
+	values := url.Values{"text": {text}, "meta": {"1"}}
+	req, err := c.newRequest(ctx, http.MethodPost, pathQuickAdd, values)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	if (res.StatusCode / 100) != 2 {
+		return nil, newAPIError(res)
+	}
+	var out Item
+	err = c.decodeBody(res, &out)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.store(out)
+	return &out, nil
+}
+
+// ItemGetResponse is the item with its related objects.
+// Ancestors is the chain of the parent items, from the direct parent to the top level one.
+// Section is nil if the item is not in a section.
+type ItemGetResponse struct {
+	Item      Item     `json:"item"`
+	Ancestors []Item   `json:"ancestors"`
+	Project   Project  `json:"project"`
+	Section   *Section `json:"section"`
+	Notes     []Note   `json:"notes"`
+}
+
+// Get fetches the item with its ancestors, project, section and notes, and stores them in the caches.
+// It returns *NotFoundError if the item does not exist.
+func (c *ItemClient) Get(ctx context.Context, id ID) (*ItemGetResponse, error) {
+	values := url.Values{"item_id": {id.String()}}
+	req, err := c.newRequest(ctx, http.MethodGet, pathItemsGet, values)
 	if err != nil {
 		return nil, err
 	}
 	var out ItemGetResponse
-	err = decodeBody(res, &out)
+	res, cached, err := c.doETag(req, &out)
 	if err != nil {
 		return nil, err
 	}
+	if !cached {
+		switch {
+		case res.StatusCode == http.StatusNotFound:
+			res.Body.Close()
+			return nil, &NotFoundError{Resource: "item", ID: id}
+		case (res.StatusCode / 100) != 2:
+			return nil, newAPIError(res)
+		}
+		if err = c.decodeBody(res, &out); err != nil {
+			return nil, err
+		}
+		c.storeETag(req, res, &out)
+	}
+	c.cache.store(out.Item)
+	for _, item := range out.Ancestors {
+		c.cache.store(item)
+	}
+	if !out.Project.ID.IsZero() {
+		c.Project.cache.store(out.Project)
+	}
+	if out.Section != nil {
+		c.Section.cache.store(*out.Section)
+	}
+	for _, note := range out.Notes {
+		c.Note.cache.store(note)
+	}
 	return &out, nil
 }
 
 func (c *ItemClient) GetCompleted(ctx context.Context, projectID ID) (*[]Item, error) {
 	values := url.Values{"project_id": {projectID.String()}}
-	req, err := c.newRequest(ctx, http.MethodGet, "items/get_completed", values)
+	req, err := c.newRequest(ctx, http.MethodGet, pathItemsGetCompleted, values)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	var out []Item
-	err = decodeBody(res, &out)
+	err = c.decodeBody(res, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -240,10 +750,85 @@ func (c *ItemClient) GetAll() []Item {
 	return c.cache.getAll()
 }
 
+// Resolve returns a copy of the cached item. Use Update to modify it.
 func (c *ItemClient) Resolve(id ID) *Item {
 	return c.cache.resolve(id)
 }
 
+// Subtasks returns the cached items whose parent is the given item, ordered by child order.
+func (c ItemClient) Subtasks(parentID ID) []Item {
+	var res []Item
+	if parentID.IsZero() {
+		return res
+	}
+	for _, item := range c.GetAll() {
+		if item.ParentID == parentID {
+			res = append(res, item)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].ChildOrder < res[j].ChildOrder
+	})
+	return res
+}
+
+// Parent returns the cached parent of the given item, or nil for a top-level item.
+func (c ItemClient) Parent(id ID) *Item {
+	item := c.Resolve(id)
+	if item == nil || item.ParentID.IsZero() {
+		return nil
+	}
+	return c.Resolve(item.ParentID)
+}
+
+// Descendants returns the cached subtasks of the given item recursively, in depth-first order.
+// It stops at an item visited already, so that a malformed hierarchy does not loop forever.
+func (c ItemClient) Descendants(id ID) []Item {
+	var res []Item
+	seen := map[ID]bool{id: true}
+	var walk func(id ID)
+	walk = func(id ID) {
+		for _, child := range c.Subtasks(id) {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			res = append(res, child)
+			walk(child.ID)
+		}
+	}
+	walk(id)
+	return res
+}
+
+// AddSubtask adds the item under the parent, in the project and section of the cached parent.
+// The parent may be an item added in the same batch by its temporary id, which the server resolves
+// in order. A temporary id of an item committed already is replaced with the id assigned by the server.
+func (c *ItemClient) AddSubtask(parent ID, item Item) (*Item, error) {
+	if parent.IsZero() {
+		return nil, errors.New("add subtask requires a parent id")
+	}
+	if IsTempID(parent) {
+		if resolved := c.ResolveTempID(parent); resolved != parent {
+			parent = resolved
+		} else if !c.queuedTempID(parent) {
+			return nil, fmt.Errorf("%w: parent %s is not added", ErrInvalidTempID, parent)
+		}
+	}
+	if item.ID.IsZero() {
+		item.ID = GenerateTempID()
+	}
+	if item.ID == parent {
+		return nil, errors.New("item cannot be a subtask of itself")
+	}
+	item.ParentID = parent
+	if p := c.Resolve(parent); p != nil {
+		item.ProjectID = p.ProjectID
+		item.SectionID = p.SectionID
+	}
+	return c.Add(item)
+}
+
 func (c ItemClient) FindByProjectIDs(ids []ID) []Item {
 	var res []Item
 	for _, i := range c.GetAll() {
@@ -279,48 +864,273 @@ func (c ItemClient) FindByDueDate(time Time) []Item {
 
 type itemCache struct {
 	cache *[]Item
+	// index maps ids to positions in cache.
+	index map[ID]int
+	// pending holds items before local changes by id, rolled back if the changes are rejected.
+	pending map[ID]*Item
+	// linked holds the ids of the items changed along with the item by its command, e.g. moved subtasks,
+	// which are settled along with it.
+	linked map[ID][]ID
+	mu     sync.RWMutex
+	notify *notifier
 }
 
 func (c *itemCache) getAll() []Item {
-	return *c.cache
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Item, len(*c.cache))
+	copy(res, *c.cache)
+	return res
 }
 
+// resolve returns a copy of the cached item, so that it is safe to read
+// while the cache is updated. Modifying it does not affect the cache.
 func (c *itemCache) resolve(id ID) *Item {
-	for _, item := range *c.cache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	i, ok := c.lookup(id)
+	if !ok {
+		return nil
+	}
+	res := (*c.cache)[i]
+	return &res
+}
+
+// lookup returns the position of the item in the cache. The caller must hold the lock.
+// The cache is scanned if it is not indexed yet.
+func (c *itemCache) lookup(id ID) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[id]
+		return i, ok
+	}
+	for i, item := range *c.cache {
 		if item.ID == id {
-			return &item
+			return i, true
 		}
 	}
-	return nil
+	return 0, false
+}
+
+// set swaps the cache for the items and indexes them. The caller must hold the lock.
+func (c *itemCache) set(items []Item) {
+	c.cache = &items
+	c.index = indexItems(items)
+}
+
+func indexItems(items []Item) map[ID]int {
+	res := make(map[ID]int, len(items))
+	for i, item := range items {
+		res[item.ID] = i
+	}
+	return res
 }
 
 func (c *itemCache) store(item Item) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("items")
 	// sync api do not returns deleted items.
 	// so remove deleted items from cache too.
+	i, ok := c.lookup(item.ID)
+	switch {
+	case item.IsDeleted.Bool():
+		if ok {
+			c.removeAt(i)
+		}
+	case ok:
+		(*c.cache)[i] = item
+	default:
+		*c.cache = append(*c.cache, item)
+		if c.index != nil {
+			c.index[item.ID] = len(*c.cache) - 1
+		}
+	}
+}
+
+func (c *itemCache) remove(item Item) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("items")
+	if i, ok := c.lookup(item.ID); ok {
+		c.removeAt(i)
+	}
+}
+
+// removeAt removes the item at the position, and shifts the positions of the following ones in the index.
+// The caller must hold the lock.
+func (c *itemCache) removeAt(i int) {
+	items := *c.cache
+	id := items[i].ID
+	copy(items[i:], items[i+1:])
+	items = items[:len(items)-1]
+	*c.cache = items
+	if c.index != nil {
+		delete(c.index, id)
+		for j := i; j < len(items); j++ {
+			c.index[items[j].ID] = j
+		}
+	}
+}
+
+func (c *itemCache) replaceTempIDs(mapping map[ID]ID) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("items")
+	res := make([]Item, len(*c.cache))
+	copy(res, *c.cache)
+	for i, item := range res {
+		if id, ok := mapping[item.ID]; ok {
+			res[i].ID = id
+		}
+		res[i].ProjectID = mapTempID(item.ProjectID, mapping)
+		res[i].SectionID = mapTempID(item.SectionID, mapping)
+		res[i].ParentID = mapTempID(item.ParentID, mapping)
+		if len(item.Labels) != 0 {
+			labels := make([]ID, len(item.Labels))
+			for j, label := range item.Labels {
+				labels[j] = mapTempID(label, mapping)
+			}
+			res[i].Labels = labels
+		}
+	}
+	c.set(res)
+}
+
+// replace swaps the cache for the given items at once.
+// Cached items with temporary ids are kept, since they are not committed yet.
+func (c *itemCache) replace(items []Item) {
 	var res []Item
-	isNew := true
-	for _, i := range *c.cache {
-		if i.Equal(item) {
-			if !item.IsDeleted {
-				res = append(res, item)
+	for _, item := range items {
+		if !item.IsDeleted.Bool() {
+			res = append(res, item)
+		}
+	}
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("items")
+	for _, item := range *c.cache {
+		if IsTempID(item.ID) {
+			res = append(res, item)
+		}
+	}
+	c.set(res)
+}
+
+// reorder sets the child orders of the cached items, and sorts them by the new orders
+// in the positions they had. The other items are kept in place.
+func (c *itemCache) reorder(orders map[ID]int) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("items")
+	var positions []int
+	var items []Item
+	for i, item := range *c.cache {
+		if order, ok := orders[item.ID]; ok {
+			item.ChildOrder = order
+			positions = append(positions, i)
+			items = append(items, item)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ChildOrder < items[j].ChildOrder
+	})
+	for k, i := range positions {
+		(*c.cache)[i] = items[k]
+		if c.index != nil {
+			c.index[items[k].ID] = i
+		}
+	}
+}
+
+// reset sets the cache to the given items, dropping local changes.
+func (c *itemCache) reset(items []Item) {
+	res := make([]Item, len(items))
+	copy(res, items)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("items")
+	c.set(res)
+	c.pending = nil
+	c.linked = nil
+}
+
+// markPending keeps the item before the first local change, to roll it back.
+// The linked items changed along with it by the same command are kept as well,
+// unless they have local changes of their own already.
+func (c *itemCache) markPending(id ID, linked ...ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.keepPending(id) {
+		for _, l := range linked {
+			if c.keepPending(l) {
+				if c.linked == nil {
+					c.linked = map[ID][]ID{}
+				}
+				c.linked[id] = append(c.linked[id], l)
 			}
-			isNew = false
-		} else {
-			res = append(res, i)
 		}
 	}
-	if isNew && !item.IsDeleted.Bool() {
-		res = append(res, item)
+}
+
+// keepPending keeps the item before the first local change, and reports whether it is kept now.
+// The caller must hold the lock.
+func (c *itemCache) keepPending(id ID) bool {
+	if _, ok := c.pending[id]; ok {
+		return false
+	}
+	if c.pending == nil {
+		c.pending = map[ID]*Item{}
 	}
-	c.cache = &res
+	c.pending[id] = nil
+	if i, ok := c.lookup(id); ok {
+		original := (*c.cache)[i]
+		c.pending[id] = &original
+	}
+	return true
 }
 
-func (c *itemCache) remove(item Item) {
-	var res []Item
-	for _, i := range *c.cache {
-		if !i.Equal(item) {
-			res = append(res, i)
+// settle forgets the local change of the item and its linked items committed successfully,
+// or rolls them back if the command failed.
+func (c *itemCache) settle(id ID, ok bool) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.settlePending(id, ok)
+	for _, l := range c.linked[id] {
+		c.settlePending(l, ok)
+	}
+	delete(c.linked, id)
+}
+
+// settlePending forgets or rolls back the local change of the item. The caller must hold the lock.
+func (c *itemCache) settlePending(id ID, ok bool) {
+	original, pending := c.pending[id]
+	if !pending {
+		return
+	}
+	delete(c.pending, id)
+	if ok {
+		return
+	}
+	c.notify.record("items")
+	i, cached := c.lookup(id)
+	switch {
+	case original == nil:
+		if cached {
+			c.removeAt(i)
+		}
+	case cached:
+		(*c.cache)[i] = *original
+	default:
+		*c.cache = append(*c.cache, *original)
+		if c.index != nil {
+			c.index[id] = len(*c.cache) - 1
 		}
 	}
-	c.cache = &res
 }
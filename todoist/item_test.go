@@ -0,0 +1,629 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestItemClient_QuickAdd(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/quick/add" {
+			t.Errorf("Expect %s, but got %s", "/quick/add", r.URL.Path)
+		}
+		if text := r.FormValue("text"); text != "Buy milk tomorrow p1" {
+			t.Errorf("Unexpect text: %s", text)
+		}
+		w.Write([]byte(`{"id": 100, "content": "Buy milk", "priority": 4, "due": {"date": "2020-01-02", "string": "tomorrow"}}`))
+	})
+	defer teardown()
+
+	item, err := client.Item.QuickAdd(context.Background(), "Buy milk tomorrow p1")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if item.Content != "Buy milk" || item.Due.String != "tomorrow" || item.Due.Date.IsZero() {
+		t.Errorf("Unexpect item: %v", item)
+	}
+	if client.Item.Resolve("100") == nil {
+		t.Error("Expect item to be cached")
+	}
+}
+
+func TestItemClient_Get(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/items/get" {
+			t.Errorf("Expect %s, but got %s", "/items/get", r.URL.Path)
+		}
+		if r.FormValue("item_id") != "3" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"item": {"id": 3, "content": "child", "parent_id": 2, "project_id": 10, "section_id": 20},
+			"ancestors": [{"id": 2, "content": "parent", "parent_id": 1}, {"id": 1, "content": "root"}],
+			"project": {"id": 10, "name": "Inbox"},
+			"section": {"id": 20, "name": "Todo", "project_id": 10},
+			"notes": [{"id": 30, "item_id": 3, "content": "comment"}]
+		}`))
+	})
+	defer teardown()
+
+	res, err := client.Item.Get(context.Background(), "3")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if res.Item.Content != "child" || len(res.Ancestors) != 2 || res.Section == nil || len(res.Notes) != 1 {
+		t.Errorf("Unexpect response: %v", res)
+	}
+	for _, id := range []ID{"1", "2", "3"} {
+		if client.Item.Resolve(id) == nil {
+			t.Errorf("Expect item %s to be cached", id)
+		}
+	}
+	if client.Project.Resolve("10") == nil || client.Section.Resolve("20") == nil || client.Note.Resolve("30") == nil {
+		t.Error("Expect related objects to be cached")
+	}
+
+	_, err = client.Item.Get(context.Background(), "4")
+	var notFound *NotFoundError
+	if !errors.As(err, &notFound) || notFound.ID != "4" || !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expect not found error, but got %v", err)
+	}
+}
+
+func TestItemClient_Move(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	section := Section{Name: "section", ProjectID: "2"}
+	section.ID = "3"
+	client.Section.cache.store(section)
+	item := Item{ProjectID: "1", ParentID: "4", Content: "item"}
+	item.ID = "10"
+	client.Item.cache.store(item)
+
+	for _, opts := range []*ItemMoveOpts{nil, {}, {ProjectID: "2", SectionID: "3"}} {
+		if err := client.Item.Move("10", opts); err == nil {
+			t.Errorf("Expect error for %v, but got nil", opts)
+		}
+	}
+	if len(client.queue) != 0 {
+		t.Fatalf("Expect no command, but got %d", len(client.queue))
+	}
+
+	if err := client.Item.Move("10", &ItemMoveOpts{SectionID: "3"}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	args, ok := client.queue[0].Args.(map[string]interface{})
+	if !ok || len(args) != 2 || args["section_id"] != ID("3") {
+		t.Errorf("Unexpect args: %v", client.queue[0].Args)
+	}
+	moved := client.Item.Resolve("10")
+	if moved.SectionID != "3" || moved.ProjectID != "2" || !moved.ParentID.IsZero() {
+		t.Errorf("Unexpect cached item: %v", moved)
+	}
+}
+
+func TestItemClient_Complete(t *testing.T) {
+	var commands []Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		w.Write([]byte(`{"sync_token": "next-token", "items": [
+			{"id": 1, "content": "recurring", "due": {"date": "2020-01-09", "string": "every week", "is_recurring": true}},
+			{"id": 2, "content": "once", "checked": 1}
+		]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	recurring := Item{Content: "recurring", Due: Due{String: "every week", IsRecurring: true}}
+	recurring.ID = "1"
+	once := Item{Content: "once"}
+	once.ID = "2"
+	client.Item.cache.store(recurring)
+	client.Item.cache.store(once)
+
+	client.Item.Complete("1", Time{}, false)
+	client.Item.Complete("2", Time{}, false)
+	if !client.Item.Resolve("2").Checked {
+		t.Error("Expect non-recurring item to be checked before commit")
+	}
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(commands) != 2 || commands[0].Type != "item_close" || commands[1].Type != "item_complete" {
+		t.Errorf("Unexpect commands: %v", commands)
+	}
+	item := client.Item.Resolve("1")
+	if item == nil || item.Checked || item.Due.Date.Format("2006-01-02") != "2020-01-09" {
+		t.Errorf("Expect recurring item with the next due date, but got %v", item)
+	}
+	if item := client.Item.Resolve("2"); item == nil || !item.Checked {
+		t.Errorf("Expect non-recurring item to be checked, but got %v", item)
+	}
+
+	client.Item.Uncomplete("2")
+	if client.Item.Resolve("2").Checked {
+		t.Error("Expect item to be unchecked")
+	}
+}
+
+func TestItemClient_ReorderIDs(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for i, id := range []ID{"1", "2", "3"} {
+		item := Item{Content: "item", ChildOrder: i + 1}
+		item.ID = id
+		client.Item.cache.store(item)
+	}
+
+	if err := client.Item.ReorderIDs([]ID{"3", "1", "2"}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	b, err := json.Marshal(client.queue[0].Args)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	expect := `{"items":[{"id":"3","child_order":1},{"id":"1","child_order":2},{"id":"2","child_order":3}]}`
+	if client.queue[0].Type != "item_reorder" || string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	for id, order := range map[ID]int{"1": 2, "2": 3, "3": 1} {
+		if item := client.Item.Resolve(id); item.ChildOrder != order {
+			t.Errorf("Expect %d, but got %d", order, item.ChildOrder)
+		}
+	}
+	var ids []ID
+	for _, item := range client.Item.GetAll() {
+		ids = append(ids, item.ID)
+	}
+	if expect := []ID{"3", "1", "2"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+	if err := client.Item.Reorder(nil); err == nil {
+		t.Error("Expect error for no items, but got nil")
+	}
+}
+
+func TestItemClient_CompleteMany(t *testing.T) {
+	var sizes []int
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		for _, command := range commands {
+			if command.Type != "item_complete" {
+				t.Errorf("Expect %s, but got %s", "item_complete", command.Type)
+			}
+		}
+		sizes = append(sizes, len(commands))
+		w.Write([]byte(`{"sync_token": "next-token"}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	var ids []ID
+	for i := 1; i <= 300; i++ {
+		ids = append(ids, ID(strconv.Itoa(i)))
+	}
+	n, err := client.Item.CompleteMany(ids)
+	if err != nil || n != 300 || len(client.Pending()) != 300 {
+		t.Fatalf("Expect %d commands, but got %d (%v)", 300, len(client.Pending()), err)
+	}
+	if err = client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := []int{100, 100, 100}; !reflect.DeepEqual(sizes, expect) {
+		t.Errorf("Expect %v, but got %v", expect, sizes)
+	}
+}
+
+func TestItemClient_DeleteManyMoveMany(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	ids := []ID{"1", "2", "3"}
+
+	if n, err := client.Item.MoveMany(ids, &ItemMoveOpts{}); err == nil || n != 0 || len(client.Pending()) != 0 {
+		t.Errorf("Expect nothing to be queued, but got %d (%v)", len(client.Pending()), err)
+	}
+	if n, err := client.Item.MoveMany(nil, nil); err == nil || n != 0 {
+		t.Errorf("Expect error for nil opts, but got %d (%v)", n, err)
+	}
+	if n, err := client.Item.MoveMany(ids, &ItemMoveOpts{ProjectID: "10"}); err != nil || n != 3 {
+		t.Errorf("Expect %d, but got %d (%v)", 3, n, err)
+	}
+	if n, err := client.Item.DeleteMany(ids); err != nil || n != 3 {
+		t.Errorf("Expect %d, but got %d (%v)", 3, n, err)
+	}
+	var types []string
+	for _, command := range client.Pending() {
+		types = append(types, command.Type)
+	}
+	expect := []string{"item_move", "item_move", "item_move", "item_delete", "item_delete", "item_delete"}
+	if !reflect.DeepEqual(types, expect) {
+		t.Errorf("Expect %v, but got %v", expect, types)
+	}
+}
+
+func TestItemClient_SetDeadline(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	var item Item
+	if err := json.Unmarshal([]byte(`{"id": 1, "content": "report", "deadline": {"date": "2020-01-02", "lang": "en"}}`), &item); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	date, ok := item.DeadlineDate()
+	if !ok || date.Format("2006-01-02") != "2020-01-02" || item.Deadline.Lang != "en" {
+		t.Errorf("Unexpect deadline: %v", item.Deadline)
+	}
+	client.Item.cache.store(item)
+
+	client.Item.SetDeadline("1", time.Date(2020, 2, 3, 15, 0, 0, 0, time.UTC))
+	client.Item.SetDeadline("2", time.Time{})
+	var args []string
+	for _, command := range client.Pending() {
+		b, _ := json.Marshal(command.Args)
+		args = append(args, string(b))
+	}
+	expect := []string{`{"deadline":{"date":"2020-02-03"},"id":"1"}`, `{"deadline":null,"id":"2"}`}
+	if !reflect.DeepEqual(args, expect) {
+		t.Errorf("Expect %v, but got %v", expect, args)
+	}
+	if date, ok := client.Item.Resolve("1").DeadlineDate(); !ok || date.Day() != 3 {
+		t.Errorf("Expect cached deadline to be updated, but got %v", date)
+	}
+	if _, ok := (Item{}).DeadlineDate(); ok {
+		t.Error("Expect no deadline")
+	}
+}
+
+func TestItemClient_SetDuration(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	duration, err := NewDuration(2, DurationUnitDay)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if _, err = NewDuration(1, "week"); err == nil {
+		t.Error("Expect error for unit")
+	}
+	item, _ := NewItem("meeting", &NewItemOpts{
+		Due:      NewFloatingDue(time.Date(2020, 1, 2, 15, 0, 0, 0, time.Local)),
+		Duration: duration,
+	})
+	client.Item.Add(*item)
+	b, _ := json.Marshal(client.Pending()[0].Args)
+	var added Item
+	if err = json.Unmarshal(b, &added); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if minutes, ok := added.DurationMinutes(); !ok || minutes != 2*24*60 || added.Due.IsFullDay() {
+		t.Errorf("Unexpect item: %s", string(b))
+	}
+
+	client.Item.SetDuration(item.ID, 45)
+	client.Item.SetDuration(item.ID, 0)
+	var args []string
+	for _, command := range client.Pending()[1:] {
+		b, _ := json.Marshal(command.Args)
+		args = append(args, string(b))
+	}
+	expect := []string{
+		`{"duration":{"amount":45,"unit":"minute"},"id":"` + item.ID.String() + `"}`,
+		`{"duration":null,"id":"` + item.ID.String() + `"}`,
+	}
+	if !reflect.DeepEqual(args, expect) {
+		t.Errorf("Expect %v, but got %v", expect, args)
+	}
+	if _, ok := client.Item.Resolve(item.ID).DurationMinutes(); ok {
+		t.Error("Expect duration to be cleared")
+	}
+}
+
+func TestItemClient_GetTodayOrdered(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	client.user.store(User{TzInfo: TzInfo{Timezone: "Asia/Tokyo", Hours: 9}})
+	zoned, err := NewZonedDue(time.Date(2020, 1, 2, 16, 0, 0, 0, time.UTC), "Europe/London")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	for _, i := range []struct {
+		id       ID
+		due      Due
+		dayOrder int
+		checked  bool
+	}{
+		{"1", NewFullDayDue(time.Date(2020, 1, 3, 0, 0, 0, 0, time.Local)), 2, false},
+		{"2", NewFullDayDue(time.Date(2020, 1, 2, 0, 0, 0, 0, time.Local)), 0, false},
+		{"3", zoned, 1, false},
+		{"4", NewFullDayDue(time.Date(2020, 1, 3, 0, 0, 0, 0, time.Local)), 0, true},
+		{"5", Due{}, 0, false},
+	} {
+		item := Item{Due: i.due, DayOrder: i.dayOrder, Checked: IntBool(i.checked)}
+		item.ID = i.id
+		client.Item.cache.store(item)
+	}
+
+	// 2020-01-03 05:00 in Tokyo
+	now := time.Date(2020, 1, 2, 20, 0, 0, 0, time.UTC)
+	var ids []ID
+	for _, item := range client.Item.GetTodayOrdered(now) {
+		ids = append(ids, item.ID)
+	}
+	if expect := []ID{"3", "1"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+
+	client.Item.ReorderDay([]ItemDayOrder{{ID: "1", DayOrder: 0}})
+	if item := client.Item.Resolve("1"); item.DayOrder != 0 {
+		t.Errorf("Expect %d, but got %d", 0, item.DayOrder)
+	}
+	b, _ := json.Marshal(client.Pending()[0].Args)
+	if expect := `{"ids_to_orders":{"1":0}}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+}
+
+func TestItemClient_Subtasks(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for _, item := range []Item{
+		{Entity: Entity{ID: "1"}, ProjectID: "10", Content: "root"},
+		{Entity: Entity{ID: "2"}, ProjectID: "10", ParentID: "1", ChildOrder: 2, Content: "second"},
+		{Entity: Entity{ID: "3"}, ProjectID: "10", ParentID: "1", ChildOrder: 1, Content: "first"},
+		{Entity: Entity{ID: "4"}, ProjectID: "10", ParentID: "3", Content: "grandchild"},
+		// a malformed cycle
+		{Entity: Entity{ID: "5"}, ProjectID: "10", ParentID: "6"},
+		{Entity: Entity{ID: "6"}, ProjectID: "10", ParentID: "5"},
+	} {
+		client.Item.cache.store(item)
+	}
+
+	subtasks := client.Item.Subtasks("1")
+	if len(subtasks) != 2 || subtasks[0].ID != "3" || subtasks[1].ID != "2" {
+		t.Errorf("Unexpect subtasks: %v", subtasks)
+	}
+	if parent := client.Item.Parent("4"); parent == nil || parent.ID != "3" {
+		t.Errorf("Unexpect parent: %v", parent)
+	}
+	if parent := client.Item.Parent("1"); parent != nil {
+		t.Errorf("Expect nil, but got %v", parent)
+	}
+	var ids []ID
+	for _, item := range client.Item.Descendants("1") {
+		ids = append(ids, item.ID)
+	}
+	if expect := []ID{"3", "4", "2"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+	if descendants := client.Item.Descendants("5"); len(descendants) != 1 || descendants[0].ID != "6" {
+		t.Errorf("Unexpect descendants: %v", descendants)
+	}
+}
+
+func TestItemClient_AddSubtask(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	parent, err := NewItem("parent", &NewItemOpts{ProjectID: "10", SectionID: "20"})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if _, err = client.Item.Add(*parent); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	child, err := NewItem("child", &NewItemOpts{})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	added, err := client.Item.AddSubtask(parent.ID, *child)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if added.ParentID != parent.ID || added.ProjectID != "10" || added.SectionID != "20" {
+		t.Errorf("Unexpect item: %v", added)
+	}
+	if len(client.queue) != 2 || client.queue[1].TempID != child.ID {
+		t.Fatalf("Unexpect queue: %v", client.queue)
+	}
+	b, err := json.Marshal(client.queue[1].Args)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	var args map[string]interface{}
+	if err = json.Unmarshal(b, &args); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if args["parent_id"] != parent.ID.String() {
+		t.Errorf("Expect %s, but got %v", parent.ID, args["parent_id"])
+	}
+
+	if _, err = client.Item.AddSubtask(GenerateTempID(), Item{Content: "orphan"}); !errors.Is(err, ErrInvalidTempID) {
+		t.Errorf("Expect %s, but got %v", ErrInvalidTempID, err)
+	}
+}
+
+func TestItemClient_AddLabel(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for _, label := range []Label{{Entity: Entity{ID: "1"}, Name: "work"}, {Entity: Entity{ID: "2"}, Name: "home"}} {
+		client.Label.cache.store(label)
+	}
+	// the labels are referred by names by some versions of the API.
+	client.Item.cache.store(Item{Entity: Entity{ID: "10"}, Content: "item", Labels: []ID{"work"}})
+
+	if err := client.Item.AddLabel("10", "@home"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.AddLabel("10", "2"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.RemoveLabel("10", "1"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.RemoveLabel("10", "work"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.AddLabel("20", "1"); err == nil {
+		t.Error("Expect error for an uncached item, but got nil")
+	}
+
+	var labels []string
+	for _, command := range client.Pending() {
+		b, _ := json.Marshal(command.Args.(map[string]interface{})["labels"])
+		labels = append(labels, string(b))
+	}
+	if expect := []string{`["1","2"]`, `["2"]`}; !reflect.DeepEqual(labels, expect) {
+		t.Errorf("Expect %v, but got %v", expect, labels)
+	}
+	if item := client.Item.Resolve("10"); !reflect.DeepEqual(item.Labels, []ID{"2"}) {
+		t.Errorf("Expect %v, but got %v", []ID{"2"}, item.Labels)
+	}
+}
+
+func TestItemClient_GetByLabel(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	client.Label.cache.store(Label{Entity: Entity{ID: "1"}, Name: "work"})
+	for _, item := range []Item{
+		{Entity: Entity{ID: "10"}, Labels: []ID{"1"}, ChildOrder: 3},
+		{Entity: Entity{ID: "11"}, Labels: []ID{"home", "work"}, ChildOrder: 1},
+		{Entity: Entity{ID: "12"}, Labels: []ID{"@work"}, ChildOrder: 2},
+		{Entity: Entity{ID: "13"}, Labels: []ID{"home"}},
+	} {
+		client.Item.cache.store(item)
+	}
+
+	for _, items := range [][]Item{client.Item.GetByLabel("1"), client.Item.GetByLabelName("@work")} {
+		var ids []ID
+		for _, item := range items {
+			ids = append(ids, item.ID)
+		}
+		if expect := []ID{"11", "12", "10"}; !reflect.DeepEqual(ids, expect) {
+			t.Errorf("Expect %v, but got %v", expect, ids)
+		}
+	}
+	if items := client.Item.GetByLabelName("home"); len(items) != 2 {
+		t.Errorf("Unexpect items: %v", items)
+	}
+	if items := client.Item.GetByLabel("2"); items == nil || len(items) != 0 {
+		t.Errorf("Expect empty items, but got %v", items)
+	}
+}
+
+func TestItemClient_ReorderDayConflict(t *testing.T) {
+	var sent []Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		sent = append(sent, commands...)
+		status := map[UUID]interface{}{}
+		for _, command := range commands {
+			status[command.UUID] = map[string]interface{}{"error_code": 40, "error_tag": "DAY_ORDERS_CONFLICT", "error": "Day orders changed"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sync_token": "next-token", "day_orders_timestamp": "1580000000.2", "sync_status": status})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	client.updateState(&SyncState{DayOrdersTimestamp: "1580000000.1"})
+	if ts := client.DayOrdersTimestamp(); ts != "1580000000.1" {
+		t.Fatalf("Expect %s, but got %s", "1580000000.1", ts)
+	}
+
+	client.Item.ReorderDay([]ItemDayOrder{{ID: "1", DayOrder: 2}})
+	err := client.Commit(context.Background())
+	var conflictErr *ConflictError
+	if !errors.As(err, &conflictErr) || conflictErr.Resource != "day_orders" {
+		t.Fatalf("Expect ConflictError, but got %v", err)
+	}
+	if len(sent) != 1 || sent[0].Args.(map[string]interface{})["day_orders_timestamp"] != "1580000000.1" {
+		t.Errorf("Expect the timestamp to be sent, but got %v", sent)
+	}
+	if ts := client.DayOrdersTimestamp(); ts != "1580000000.2" {
+		t.Errorf("Expect %s, but got %s", "1580000000.2", ts)
+	}
+	if pending := client.Pending(); len(pending) != 0 {
+		t.Errorf("Expect the rejected orders not to be retried, but got %v", pending)
+	}
+	if failed := client.FailedCommands(); len(failed) != 1 || failed[0].Type != "item_update_day_orders" {
+		t.Errorf("Expect the rejected orders, but got %v", failed)
+	}
+
+	client.Item.ReorderDay([]ItemDayOrder{{ID: "1", DayOrder: 3}})
+	client.Commit(context.Background())
+	if len(sent) != 2 || sent[1].Args.(map[string]interface{})["day_orders_timestamp"] != "1580000000.2" {
+		t.Errorf("Expect the new timestamp to be sent, but got %v", sent)
+	}
+}
+
+func TestItemClient_UpdateFields(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		status := map[UUID]interface{}{}
+		for _, command := range commands {
+			if commandTargetID(command) == "1" {
+				status[command.UUID] = "ok"
+			} else {
+				status[command.UUID] = map[string]interface{}{"error_code": 22, "error": "Item not found"}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token":  "next-token",
+			"sync_status": status,
+		})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	for _, id := range []ID{"1", "2"} {
+		item := Item{Content: "before", ProjectID: "10", Priority: 1}
+		item.ID = id
+		client.Item.cache.store(item)
+	}
+
+	if err := client.Item.UpdateFields("1", map[string]interface{}{"project_id": "20"}); err == nil {
+		t.Error("Expect error for project_id")
+	}
+	if err := client.Item.UpdateFields("1", map[string]interface{}{"content": "after", "priority": Priority(4)}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.UpdateFields("2", map[string]interface{}{"content": "rejected"}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	pending := client.Pending()
+	if len(pending) != 2 {
+		t.Fatalf("Expect %d commands, but got %d", 2, len(pending))
+	}
+	b, _ := json.Marshal(pending[0].Args)
+	if expect := `{"content":"after","id":"1","priority":4}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	if item := client.Item.Resolve("1"); item.Content != "after" || item.Priority != 4 || item.ProjectID != "10" {
+		t.Errorf("Unexpect item: %v", item)
+	}
+	if item := client.Item.Resolve("2"); item.Content != "rejected" {
+		t.Errorf("Expect %s before commit, but got %s", "rejected", item.Content)
+	}
+
+	var commitErr *CommitError
+	if err := client.Commit(context.Background()); !errors.As(err, &commitErr) || len(commitErr.Errors) != 1 {
+		t.Fatalf("Expect 1 command error, but got %v", err)
+	}
+	if item := client.Item.Resolve("1"); item == nil || item.Content != "after" {
+		t.Errorf("Expect update to be kept, but got %v", item)
+	}
+	if item := client.Item.Resolve("2"); item == nil || item.Content != "before" {
+		t.Errorf("Expect update to be rolled back, but got %v", item)
+	}
+}
@@ -3,16 +3,19 @@ package todoist
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/fatih/color"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
+	"sync"
 )
 
 type Label struct {
 	Entity
 	Name       string  `json:"name"`
-	Color      int     `json:"color"`
+	Color      Color   `json:"color"`
 	ItemOrder  int     `json:"item_order"`
 	IsFavorite IntBool `json:"is_favorite"`
 }
@@ -22,30 +25,11 @@ func (l Label) String() string {
 }
 
 func (l Label) ColorString() string {
-	var attr color.Attribute
-	switch l.Color {
-	case 30, 31:
-		attr = color.FgHiRed
-	case 32, 33:
-		attr = color.FgHiYellow
-	case 34, 35, 36:
-		attr = color.FgHiGreen
-	case 37, 38, 39:
-		attr = color.FgHiCyan
-	case 40, 41, 42:
-		attr = color.FgHiBlue
-	case 43, 44, 45, 46:
-		attr = color.FgHiMagenta
-	case 47, 48, 49:
-		attr = color.FgHiBlack
-	default:
-		attr = color.FgWhite
-	}
-	return color.New(attr).Sprint(l.String())
+	return color.New(l.Color.attribute()).Sprint(l.String())
 }
 
 type NewLabelOpts struct {
-	Color      int
+	Color      Color
 	ItemOrder  int
 	IsFavorite IntBool
 }
@@ -54,6 +38,9 @@ func NewLabel(name string, opts *NewLabelOpts) (*Label, error) {
 	if len(name) == 0 {
 		return nil, errors.New("new label requires a name")
 	}
+	if opts == nil {
+		opts = &NewLabelOpts{}
+	}
 	label := Label{
 		Name:       name,
 		ItemOrder:  opts.ItemOrder,
@@ -61,7 +48,7 @@ func NewLabel(name string, opts *NewLabelOpts) (*Label, error) {
 	}
 	label.ID = GenerateTempID()
 	if opts.Color == 0 {
-		label.Color = 47
+		label.Color = Charcoal
 	} else {
 		label.Color = opts.Color
 	}
@@ -92,52 +79,92 @@ type LabelClient struct {
 }
 
 func (c *LabelClient) Add(label Label) (*Label, error) {
-	c.cache.store(label)
-	command := Command{
-		Type:   "label_add",
-		Args:   label,
-		UUID:   GenerateUUID(),
-		TempID: label.ID,
-	}
-	c.queue = append(c.queue, command)
+	if c.optimistic() {
+		c.cache.store(label)
+	}
+	c.enqueue("label_add", label, label.ID)
 	return &label, nil
 }
 
 func (c *LabelClient) Update(label Label) (*Label, error) {
-	command := Command{
-		Type: "label_update",
-		Args: label,
-		UUID: GenerateUUID(),
+	if err := validateName("label", label.Name); err != nil {
+		return nil, err
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue("label_update", label, "")
 	return &label, nil
 }
 
+func (c *LabelClient) SetColor(id ID, color Color) error {
+	if !color.IsValid() {
+		return fmt.Errorf("invalid color: %s", color)
+	}
+	c.enqueue("label_update", map[string]interface{}{
+		"id":    id,
+		"color": color,
+	}, "")
+	return nil
+}
+
+// Rename changes the name of the label. A leading "@" is dropped.
+func (c *LabelClient) Rename(id ID, name string) error {
+	if r := []rune(name); len(r) > 0 && string(r[0]) == "@" {
+		name = string(r[1:])
+	}
+	if err := validateName("label", name); err != nil {
+		return err
+	}
+	c.enqueue("label_update", map[string]interface{}{
+		"id":   id,
+		"name": name,
+	}, "")
+	if label := c.Resolve(id); label != nil && c.optimistic() {
+		label.Name = name
+		c.cache.store(*label)
+	}
+	return nil
+}
+
 func (c *LabelClient) Delete(id ID) error {
-	command := Command{
-		Type: "label_delete",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("label_delete", map[string]ID{"id": id}, "")
 	return nil
 }
 
+// DeleteIfExists deletes the label only if it is cached. See SectionClient.DeleteIfExists.
+func (c *LabelClient) DeleteIfExists(id ID, opts *DeleteOpts) error {
+	if c.Resolve(id) == nil {
+		return opts.notFound("label", id)
+	}
+	return c.Delete(id)
+}
+
 func (c *LabelClient) UpdateOrders(labels []Label) error {
 	args := map[ID]int{}
 	for _, label := range labels {
 		args[label.ID] = label.ItemOrder
 	}
-	command := Command{
-		Type: "label_update_orders",
-		UUID: GenerateUUID(),
-		Args: map[string]map[ID]int{
-			"id_order_mapping": args,
-		},
+	c.enqueue("label_update_orders", map[string]map[ID]int{"id_order_mapping": args}, "")
+	return nil
+}
+
+// Reorder orders the labels along the given ids, numbering item orders from 1.
+func (c *LabelClient) Reorder(ids []ID) error {
+	if len(ids) == 0 {
+		return errors.New("reorder requires labels")
+	}
+	labels := make([]Label, len(ids))
+	for i, id := range ids {
+		labels[i].ID = id
+		labels[i].ItemOrder = i + 1
+	}
+	if err := c.UpdateOrders(labels); err != nil {
+		return err
+	}
+	for _, l := range labels {
+		if label := c.Resolve(l.ID); label != nil && c.optimistic() {
+			label.ItemOrder = l.ItemOrder
+			c.cache.store(*label)
+		}
 	}
-	c.queue = append(c.queue, command)
 	return nil
 }
 
@@ -147,16 +174,16 @@ type LabelGetResponse struct {
 
 func (c *LabelClient) Get(ctx context.Context, id ID) (*LabelGetResponse, error) {
 	values := url.Values{"label_id": {id.String()}}
-	req, err := c.newRequest(ctx, http.MethodGet, "labels/get", values)
+	req, err := c.newRequest(ctx, http.MethodGet, pathLabelsGet, values)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	var out LabelGetResponse
-	err = decodeBody(res, &out)
+	err = c.decodeBody(res, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +194,7 @@ func (c *LabelClient) GetAll() []Label {
 	return c.cache.getAll()
 }
 
+// Resolve returns a copy of the cached label. Use Update to modify it.
 func (c *LabelClient) Resolve(id ID) *Label {
 	return c.cache.resolve(id)
 }
@@ -175,13 +203,22 @@ func (c LabelClient) FindByName(substr string) []Label {
 	if r := []rune(substr); len(r) > 0 && string(r[0]) == "@" {
 		substr = string(r[1:])
 	}
+	return c.cache.findByName(substr)
+}
+
+// FindByNameRegex returns the cached labels whose name matches the regular expression.
+func (c LabelClient) FindByNameRegex(pattern string) ([]Label, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
 	var res []Label
 	for _, l := range c.GetAll() {
-		if strings.Contains(l.Name, substr) {
+		if re.MatchString(l.Name) {
 			res = append(res, l)
 		}
 	}
-	return res
+	return res, nil
 }
 
 func (c LabelClient) FindOneByName(substr string) *Label {
@@ -199,46 +236,166 @@ func (c LabelClient) FindOneByName(substr string) *Label {
 
 type labelCache struct {
 	cache *[]Label
+	// index maps ids to positions in cache.
+	index  map[ID]int
+	mu     sync.RWMutex
+	notify *notifier
 }
 
 func (c *labelCache) getAll() []Label {
-	return *c.cache
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Label, len(*c.cache))
+	copy(res, *c.cache)
+	return res
+}
+
+// findByName returns copies of the cached labels whose name contains substr,
+// without copying the others.
+func (c *labelCache) findByName(substr string) []Label {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var res []Label
+	for _, l := range *c.cache {
+		if strings.Contains(l.Name, substr) {
+			res = append(res, l)
+		}
+	}
+	return res
 }
 
+// resolve returns a copy of the cached label, so that it is safe to read
+// while the cache is updated. Modifying it does not affect the cache.
 func (c *labelCache) resolve(id ID) *Label {
-	for _, label := range *c.cache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	i, ok := c.lookup(id)
+	if !ok {
+		return nil
+	}
+	res := (*c.cache)[i]
+	return &res
+}
+
+// lookup returns the position of the label in the cache. The caller must hold the lock.
+// The cache is scanned if it is not indexed yet.
+func (c *labelCache) lookup(id ID) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[id]
+		return i, ok
+	}
+	for i, label := range *c.cache {
 		if label.ID == id {
-			return &label
+			return i, true
 		}
 	}
-	return nil
+	return 0, false
+}
+
+// set swaps the cache for the labels and indexes them. The caller must hold the lock.
+func (c *labelCache) set(labels []Label) {
+	c.cache = &labels
+	c.index = indexLabels(labels)
+}
+
+func indexLabels(labels []Label) map[ID]int {
+	res := make(map[ID]int, len(labels))
+	for i, label := range labels {
+		res[label.ID] = i
+	}
+	return res
 }
 
 func (c *labelCache) store(label Label) {
-	var res []Label
-	isNew := true
-	for _, l := range *c.cache {
-		if l.Equal(label) {
-			if !label.IsDeleted {
-				res = append(res, label)
-			}
-			isNew = false
-		} else {
-			res = append(res, l)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("labels")
+	i, ok := c.lookup(label.ID)
+	switch {
+	case label.IsDeleted.Bool():
+		if ok {
+			c.removeAt(i)
+		}
+	case ok:
+		(*c.cache)[i] = label
+	default:
+		*c.cache = append(*c.cache, label)
+		if c.index != nil {
+			c.index[label.ID] = len(*c.cache) - 1
 		}
 	}
-	if isNew && !label.IsDeleted.Bool() {
-		res = append(res, label)
-	}
-	c.cache = &res
 }
 
 func (c *labelCache) remove(label Label) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("labels")
+	if i, ok := c.lookup(label.ID); ok {
+		c.removeAt(i)
+	}
+}
+
+// removeAt removes the label at the position, and shifts the positions of the following ones in the index.
+// The caller must hold the lock.
+func (c *labelCache) removeAt(i int) {
+	labels := *c.cache
+	id := labels[i].ID
+	copy(labels[i:], labels[i+1:])
+	labels = labels[:len(labels)-1]
+	*c.cache = labels
+	if c.index != nil {
+		delete(c.index, id)
+		for j := i; j < len(labels); j++ {
+			c.index[labels[j].ID] = j
+		}
+	}
+}
+
+func (c *labelCache) replaceTempIDs(mapping map[ID]ID) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("labels")
+	res := make([]Label, len(*c.cache))
+	copy(res, *c.cache)
+	for i, label := range res {
+		if id, ok := mapping[label.ID]; ok {
+			res[i].ID = id
+		}
+	}
+	c.set(res)
+}
+
+// replace swaps the cache for the given labels at once.
+// Cached labels with temporary ids are kept, since they are not committed yet.
+func (c *labelCache) replace(labels []Label) {
 	var res []Label
-	for _, l := range *c.cache {
-		if !l.Equal(label) {
-			res = append(res, l)
+	for _, label := range labels {
+		if !label.IsDeleted.Bool() {
+			res = append(res, label)
 		}
 	}
-	c.cache = &res
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("labels")
+	for _, label := range *c.cache {
+		if IsTempID(label.ID) {
+			res = append(res, label)
+		}
+	}
+	c.set(res)
+}
+
+// reset sets the cache to the given labels, dropping local changes.
+func (c *labelCache) reset(labels []Label) {
+	res := make([]Label, len(labels))
+	copy(res, labels)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("labels")
+	c.set(res)
 }
@@ -0,0 +1,52 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLabelClient_Rename(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	label := Label{Name: "before"}
+	label.ID = "1"
+	client.Label.cache.store(label)
+
+	if err := client.Label.Rename("1", "@after"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Label.Rename("1", "@"); err == nil {
+		t.Error("Expect error for empty name, but got nil")
+	}
+	if l := client.Label.Resolve("1"); l.Name != "after" {
+		t.Errorf("Expect %s, but got %s", "after", l.Name)
+	}
+	if labels := client.Label.FindByName("@aft"); len(labels) != 1 {
+		t.Errorf("Expect %d label, but got %v", 1, labels)
+	}
+}
+
+func TestLabelClient_Reorder(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for _, id := range []ID{"1", "2"} {
+		label := Label{Name: "label"}
+		label.ID = id
+		client.Label.cache.store(label)
+	}
+
+	if err := client.Label.Reorder([]ID{"2", "1"}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	b, err := json.Marshal(client.queue[0].Args)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	expect := `{"id_order_mapping":{"1":2,"2":1}}`
+	if client.queue[0].Type != "label_update_orders" || string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	if l := client.Label.Resolve("2"); l.ItemOrder != 1 {
+		t.Errorf("Expect %d, but got %d", 1, l.ItemOrder)
+	}
+}
@@ -0,0 +1,135 @@
+package todoist
+
+import (
+	"reflect"
+	"strings"
+)
+
+// localChange is a cached resource changed by a queued command which is not committed yet.
+type localChange struct {
+	resource string
+	id       ID
+	// value is a copy of the cached resource, or nil if it is deleted locally.
+	value interface{}
+}
+
+// localChanges returns the cached resources changed by the queued commands except the sent ones,
+// so that syncs do not overwrite them with the server copies until they are committed.
+func (c *Client) localChanges(sent []Command) []localChange {
+	isSent := map[UUID]bool{}
+	for _, command := range sent {
+		isSent[command.UUID] = true
+	}
+	c.mu.Lock()
+	queue := make([]Command, len(c.queue))
+	copy(queue, c.queue)
+	c.mu.Unlock()
+
+	var res []localChange
+	seen := map[string]bool{}
+	for _, command := range queue {
+		if isSent[command.UUID] {
+			continue
+		}
+		id := commandTargetID(command)
+		if id.IsZero() {
+			continue
+		}
+		id = c.ResolveTempID(id)
+		resource := strings.SplitN(command.Type, "_", 2)[0]
+		if seen[resource+id.String()] {
+			continue
+		}
+		value := c.resolveResource(resource, id)
+		if value == nil && !strings.HasSuffix(command.Type, "_delete") {
+			continue
+		}
+		seen[resource+id.String()] = true
+		res = append(res, localChange{resource: resource, id: id, value: value})
+	}
+	return res
+}
+
+// resolveResource returns a copy of the cached resource by the prefix of command types, e.g. "item".
+func (c *Client) resolveResource(resource string, id ID) interface{} {
+	switch resource {
+	case "item":
+		if v := c.Item.cache.resolve(id); v != nil {
+			return *v
+		}
+	case "section":
+		if v := c.Section.cache.resolve(id); v != nil {
+			return *v
+		}
+	case "project":
+		if v := c.Project.cache.resolve(id); v != nil {
+			return *v
+		}
+	case "note":
+		if v := c.Note.cache.resolve(id); v != nil {
+			return *v
+		}
+	case "label":
+		if v := c.Label.cache.resolve(id); v != nil {
+			return *v
+		}
+	case "filter":
+		if v := c.Filter.cache.resolve(id); v != nil {
+			return *v
+		}
+	case "reminder":
+		if v := c.Reminder.cache.resolve(id); v != nil {
+			return *v
+		}
+	}
+	return nil
+}
+
+// restoreLocalChanges stores the local changes back into the caches after a sync,
+// unless the sync has left them as is.
+func (c *Client) restoreLocalChanges(changes []localChange) {
+	for _, change := range changes {
+		if reflect.DeepEqual(c.resolveResource(change.resource, change.id), change.value) {
+			continue
+		}
+		switch v := change.value.(type) {
+		case Item:
+			c.Item.cache.store(v)
+		case Section:
+			c.Section.cache.store(v)
+		case Project:
+			c.Project.cache.store(v)
+		case Note:
+			c.Note.cache.store(v)
+		case Label:
+			c.Label.cache.store(v)
+		case Filter:
+			c.Filter.cache.store(v)
+		case Reminder:
+			c.Reminder.cache.store(v)
+		case nil:
+			c.removeResource(change.resource, change.id)
+		}
+	}
+}
+
+// removeResource drops the resource from the cache, as it is deleted by a sync.
+func (c *Client) removeResource(resource string, id ID) {
+	e := Entity{ID: id, IsDeleted: true}
+	switch resource {
+	case "item":
+		c.Item.cache.store(Item{Entity: e})
+	case "section":
+		c.Section.cache.store(Section{Entity: e})
+	case "project":
+		c.Project.cache.store(Project{Entity: e})
+	case "note":
+		c.Note.cache.store(Note{Entity: e})
+	case "label":
+		c.Label.cache.store(Label{Entity: e})
+	case "filter":
+		c.Filter.cache.store(Filter{Entity: e})
+	case "reminder":
+		c.Reminder.cache.store(Reminder{Entity: e})
+	}
+}
@@ -0,0 +1,57 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestClient_SyncKeepsLocalChanges(t *testing.T) {
+	var response string
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(response))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	section := Section{Name: "before", ProjectID: "10"}
+	section.ID = "1"
+	client.Section.cache.store(section)
+	deleted := Section{Name: "deleted", ProjectID: "10"}
+	deleted.ID = "2"
+	client.Section.cache.store(deleted)
+
+	section.Name = "local"
+	client.Section.Update(section)
+	client.Section.Delete("2")
+
+	// others rename the sections meanwhile.
+	response = `{"sync_token": "next-token", "sections": [{"id": 1, "name": "remote", "project_id": 10}, {"id": 2, "name": "remote", "project_id": 10}]}`
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if s := client.Section.Resolve("1"); s == nil || s.Name != "local" {
+		t.Errorf("Expect pending change to win, but got %v", s)
+	}
+	if s := client.Section.Resolve("2"); s != nil {
+		t.Errorf("Expect pending delete to win, but got %v", s)
+	}
+	if sections := client.syncState.Sections; len(sections) != 2 || sections[0].Name != "remote" || sections[1].Name != "remote" {
+		t.Errorf("Expect the persisted state to keep the server copies, but got %v", sections)
+	}
+
+	response = `{"sync_token": "*", "full_sync": true, "sections": [{"id": 1, "name": "remote", "project_id": 10}]}`
+	if err := client.FullSync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if s := client.Section.Resolve("1"); s == nil || s.Name != "local" {
+		t.Errorf("Expect pending change to win full sync, but got %v", s)
+	}
+
+	response = `{"sync_token": "next-token", "sections": [{"id": 1, "name": "committed", "project_id": 10}]}`
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if s := client.Section.Resolve("1"); s == nil || s.Name != "committed" {
+		t.Errorf("Expect server copy after commit, but got %v", s)
+	}
+}
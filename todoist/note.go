@@ -1,6 +1,13 @@
 package todoist
 
-import "errors"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"sync"
+)
 
 type Note struct {
 	Entity
@@ -10,8 +17,8 @@ type Note struct {
 	Content        string          `json:"content"`
 	FileAttachment FileAttachment  `json:"file_attachment"`
 	UIDsToNotify   []ID            `json:"uids_to_notify"`
-	Posted         Time            `json:"posted"`
-	Reactions      map[string][]ID `json:"reactions"`
+	Posted         Time            `json:"posted_at"`
+	Reactions      map[string][]ID `json:"reactions,omitempty"`
 }
 
 type FileAttachment struct {
@@ -31,6 +38,9 @@ func NewNote(id ID, content string, opts *NewNoteOpts) (*Note, error) {
 	if id.IsZero() || len(content) == 0 {
 		return nil, errors.New("new note requires an item id and a content")
 	}
+	if opts == nil {
+		opts = &NewNoteOpts{}
+	}
 	note := Note{
 		ItemID:         id,
 		Content:        content,
@@ -48,39 +58,90 @@ type NoteClient struct {
 }
 
 func (c NoteClient) Add(note Note) (*Note, error) {
-	c.cache.store(note)
-	command := Command{
-		Type:   "note_add",
-		Args:   note,
-		UUID:   GenerateUUID(),
-		TempID: note.ID,
-	}
-	c.queue = append(c.queue, command)
+	if c.optimistic() {
+		c.cache.store(note)
+	}
+	c.enqueue("note_add", note, note.ID)
 	return &note, nil
 }
 
 func (c NoteClient) Update(note Note) (*Note, error) {
-	command := Command{
-		Type: "note_update",
-		Args: note,
-		UUID: GenerateUUID(),
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("note_update", note, "")
 	return &note, nil
 }
 
 func (c NoteClient) Delete(id ID) error {
-	command := Command{
-		Type: "note_delete",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("note_delete", map[string]ID{"id": id}, "")
+	return nil
+}
+
+// DeleteIfExists deletes the note only if it is cached. See SectionClient.DeleteIfExists.
+func (c NoteClient) DeleteIfExists(id ID, opts *DeleteOpts) error {
+	if c.Resolve(id) == nil {
+		return opts.notFound("note", id)
+	}
+	return c.Delete(id)
+}
+
+// Edit updates the content of the note, and the cached note as well.
+func (c NoteClient) Edit(id ID, content string) error {
+	if len(content) == 0 {
+		return errors.New("edit note requires a content")
+	}
+	c.enqueue("note_update", map[string]interface{}{
+		"id":      id,
+		"content": content,
+	}, "")
+	if note := c.Resolve(id); note != nil && c.optimistic() {
+		note.Content = content
+		c.cache.store(*note)
+	}
 	return nil
 }
 
+// Resolve returns a copy of the cached note. Use Update to modify it.
+func (c NoteClient) Resolve(id ID) *Note {
+	return c.cache.resolve(id)
+}
+
+// Reactions returns the user ids of the cached note by reaction emoji.
+// It returns an empty map for a note without reactions or not cached.
+func (c NoteClient) Reactions(id ID) map[string][]ID {
+	res := map[string][]ID{}
+	note := c.cache.resolve(id)
+	if note == nil {
+		return res
+	}
+	for emoji, uids := range note.Reactions {
+		res[emoji] = append([]ID(nil), uids...)
+	}
+	return res
+}
+
+// GetByItem fetches the notes of the item, and stores them in the cache.
+func (c NoteClient) GetByItem(ctx context.Context, itemID ID) ([]Note, error) {
+	res, err := c.Item.Get(ctx, itemID)
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range res.Notes {
+		c.cache.store(note)
+	}
+	return res.Notes, nil
+}
+
+// GetByProject fetches the notes of the project, and stores them in the cache.
+func (c NoteClient) GetByProject(ctx context.Context, projectID ID) ([]Note, error) {
+	res, err := c.Project.Get(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, note := range res.Notes {
+		c.cache.store(note)
+	}
+	return res.Notes, nil
+}
+
 // GetAllForItem returns all the cached notes that belong to the given item.
 func (c NoteClient) GetAllForItem(itemID ID) []Note {
 	var res []Note
@@ -103,29 +164,211 @@ func (c NoteClient) GetAllForProject(projectID ID) []Note {
 	return res
 }
 
+// AddWithAttachment adds a note with the file uploaded by Upload to the item.
+func (c NoteClient) AddWithAttachment(itemID ID, content string, attachment FileAttachment) (*Note, error) {
+	note, err := NewNote(itemID, content, &NewNoteOpts{FileAttachment: attachment})
+	if err != nil {
+		return nil, err
+	}
+	return c.Add(*note)
+}
+
+// UploadError is returned by Upload when the file could not be uploaded.
+type UploadError struct {
+	FileName string
+	Err      error
+}
+
+func (e *UploadError) Error() string {
+	return fmt.Sprintf("failed to upload %s: %s", e.FileName, e.Err)
+}
+
+func (e *UploadError) Unwrap() error {
+	return e.Err
+}
+
+// Upload uploads the file to attach to a note.
+// The content is streamed from r, so that a large file is not buffered in memory.
+func (c NoteClient) Upload(ctx context.Context, r io.Reader, filename string) (*FileAttachment, error) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		pw.CloseWithError(func() error {
+			if err := mw.WriteField("token", c.Token); err != nil {
+				return err
+			}
+			if err := mw.WriteField("file_name", filename); err != nil {
+				return err
+			}
+			part, err := mw.CreateFormFile("file", filename)
+			if err != nil {
+				return err
+			}
+			if _, err = io.Copy(part, r); err != nil {
+				return err
+			}
+			return mw.Close()
+		}())
+	}()
+	req, err := c.newMultipartRequest(ctx, pathUploadsAdd, pr, mw.FormDataContentType())
+	if err != nil {
+		pr.Close()
+		return nil, &UploadError{filename, err}
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, &UploadError{filename, err}
+	}
+	if (res.StatusCode / 100) != 2 {
+		return nil, &UploadError{filename, newAPIError(res)}
+	}
+	var out FileAttachment
+	if err = c.decodeBody(res, &out); err != nil {
+		return nil, &UploadError{filename, err}
+	}
+	return &out, nil
+}
+
 type noteCache struct {
 	cache *[]Note
+	// index maps ids to positions in cache.
+	index  map[ID]int
+	mu     sync.RWMutex
+	notify *notifier
 }
 
 func (c *noteCache) getAll() []Note {
-	return *c.cache
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Note, len(*c.cache))
+	copy(res, *c.cache)
+	return res
+}
+
+// resolve returns a copy of the cached note, so that it is safe to read
+// while the cache is updated. Modifying it does not affect the cache.
+func (c *noteCache) resolve(id ID) *Note {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	i, ok := c.lookup(id)
+	if !ok {
+		return nil
+	}
+	res := (*c.cache)[i]
+	return &res
+}
+
+// lookup returns the position of the note in the cache. The caller must hold the lock.
+// The cache is scanned if it is not indexed yet.
+func (c *noteCache) lookup(id ID) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[id]
+		return i, ok
+	}
+	for i, note := range *c.cache {
+		if note.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// set swaps the cache for the notes and indexes them. The caller must hold the lock.
+func (c *noteCache) set(notes []Note) {
+	c.cache = &notes
+	c.index = indexNotes(notes)
+}
+
+func indexNotes(notes []Note) map[ID]int {
+	res := make(map[ID]int, len(notes))
+	for i, note := range notes {
+		res[note.ID] = i
+	}
+	return res
 }
 
 func (c *noteCache) store(note Note) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("notes")
+	i, ok := c.lookup(note.ID)
+	switch {
+	case note.IsDeleted.Bool():
+		if ok {
+			c.removeAt(i)
+		}
+	case ok:
+		(*c.cache)[i] = note
+	default:
+		*c.cache = append(*c.cache, note)
+		if c.index != nil {
+			c.index[note.ID] = len(*c.cache) - 1
+		}
+	}
+}
+
+// removeAt removes the note at the position, and shifts the positions of the following ones in the index.
+// The caller must hold the lock.
+func (c *noteCache) removeAt(i int) {
+	notes := *c.cache
+	id := notes[i].ID
+	copy(notes[i:], notes[i+1:])
+	notes = notes[:len(notes)-1]
+	*c.cache = notes
+	if c.index != nil {
+		delete(c.index, id)
+		for j := i; j < len(notes); j++ {
+			c.index[notes[j].ID] = j
+		}
+	}
+}
+
+func (c *noteCache) replaceTempIDs(mapping map[ID]ID) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("notes")
+	res := make([]Note, len(*c.cache))
+	copy(res, *c.cache)
+	for i, note := range res {
+		if id, ok := mapping[note.ID]; ok {
+			res[i].ID = id
+		}
+		res[i].ItemID = mapTempID(note.ItemID, mapping)
+		res[i].ProjectID = mapTempID(note.ProjectID, mapping)
+	}
+	c.set(res)
+}
+
+// replace swaps the cache for the given notes at once.
+// Cached notes with temporary ids are kept, since they are not committed yet.
+func (c *noteCache) replace(notes []Note) {
 	var res []Note
-	isNew := true
-	for _, n := range *c.cache {
-		if n.Equal(note) {
-			if !note.IsDeleted {
-				res = append(res, note)
-			}
-			isNew = false
-		} else {
-			res = append(res, n)
+	for _, note := range notes {
+		if !note.IsDeleted.Bool() {
+			res = append(res, note)
 		}
 	}
-	if isNew && !note.IsDeleted.Bool() {
-		res = append(res, note)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("notes")
+	for _, note := range *c.cache {
+		if IsTempID(note.ID) {
+			res = append(res, note)
+		}
 	}
-	c.cache = &res
+	c.set(res)
+}
+
+// reset sets the cache to the given notes, dropping local changes.
+func (c *noteCache) reset(notes []Note) {
+	res := make([]Note, len(notes))
+	copy(res, notes)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("notes")
+	c.set(res)
 }
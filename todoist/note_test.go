@@ -0,0 +1,121 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNoteClient_Upload(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("token") != "test-token" {
+			t.Errorf("Expect token, but got %s", r.FormValue("token"))
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		b, _ := ioutil.ReadAll(file)
+		if string(b) != "hello" || header.Filename != "hello.txt" {
+			t.Errorf("Unexpect file: %s %s", header.Filename, string(b))
+		}
+		w.Write([]byte(`{"file_name": "hello.txt", "file_size": 5, "file_type": "text/plain", "file_url": "https://example.com/hello.txt", "upload_state": "completed"}`))
+	})
+	defer teardown()
+
+	attachment, err := client.Note.Upload(context.Background(), strings.NewReader("hello"), "hello.txt")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if attachment.FileSize != 5 || attachment.FileURL != "https://example.com/hello.txt" {
+		t.Errorf("Unexpect attachment: %v", attachment)
+	}
+	note, err := client.Note.AddWithAttachment("1", "see attachment", *attachment)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if note.FileAttachment.FileURL != attachment.FileURL || len(client.queue) != 1 {
+		t.Errorf("Expect note_add command with the attachment, but got %v", client.queue)
+	}
+}
+
+func TestNoteClient_UploadError(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	})
+	defer teardown()
+
+	_, err := client.Note.Upload(context.Background(), strings.NewReader("hello"), "hello.txt")
+	if _, ok := err.(*UploadError); !ok {
+		t.Errorf("Expect *UploadError, but got %v", err)
+	}
+}
+
+func TestNoteClient_GetByItem(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/items/get" || r.FormValue("item_id") != "1" {
+			t.Errorf("Unexpect request: %s", r.URL)
+		}
+		w.Write([]byte(`{"item": {"id": 1, "content": "item"}, "notes": [
+			{"id": 10, "item_id": 1, "posted_uid": 100, "content": "first", "posted_at": "2020-01-02T03:04:05Z"}
+		]}`))
+	})
+	defer teardown()
+
+	notes, err := client.Note.GetByItem(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(notes) != 1 || notes[0].Content != "first" || notes[0].PostedUID != "100" || notes[0].Posted.IsZero() {
+		t.Fatalf("Unexpect notes: %v", notes)
+	}
+	if cached := client.Note.GetAllForItem("1"); len(cached) != 1 {
+		t.Errorf("Expect notes to be cached, but got %v", cached)
+	}
+
+	if err = client.Note.Edit("10", "edited"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if note := client.Note.Resolve("10"); note.Content != "edited" {
+		t.Errorf("Expect %s, but got %s", "edited", note.Content)
+	}
+	if len(client.queue) != 1 || client.queue[0].Type != "note_update" {
+		t.Errorf("Unexpect queue: %v", client.queue)
+	}
+}
+
+func TestNoteClient_Reactions(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpect request: %s", r.URL)
+	})
+	defer teardown()
+
+	var notes []Note
+	if err := json.Unmarshal([]byte(`[
+		{"id": 10, "item_id": 1, "content": "liked", "reactions": {"👍": [100, "101"]}},
+		{"id": 11, "item_id": 1, "content": "null", "reactions": null},
+		{"id": 12, "item_id": 1, "content": "absent"}
+	]`), &notes); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	for _, note := range notes {
+		client.Note.cache.store(note)
+	}
+
+	reactions := client.Note.Reactions("10")
+	if uids := reactions["👍"]; len(uids) != 2 || uids[0] != "100" || uids[1] != "101" {
+		t.Errorf("Unexpect reactions: %v", reactions)
+	}
+	reactions["👍"][0] = "999"
+	if uid := client.Note.Resolve("10").Reactions["👍"][0]; uid != "100" {
+		t.Errorf("Expect %s, but got %s", "100", uid)
+	}
+	for _, id := range []ID{"11", "12", "13"} {
+		if reactions := client.Note.Reactions(id); reactions == nil || len(reactions) != 0 {
+			t.Errorf("Unexpect reactions of %s: %v", id, reactions)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package todoist
+
+import "sync"
+
+// notifier delivers changes of caches to the listeners registered by Client.OnChange.
+// Caches record a change while holding their lock, and flush after releasing it,
+// so that listeners are called in the order of changes and may access caches.
+type notifier struct {
+	mu        sync.Mutex
+	listeners map[int]func(resource string)
+	nextID    int
+	events    []string
+	draining  bool
+}
+
+func newNotifier() *notifier {
+	return &notifier{listeners: map[int]func(resource string){}}
+}
+
+func (n *notifier) subscribe(f func(resource string)) func() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	id := n.nextID
+	n.nextID++
+	n.listeners[id] = f
+	return func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+		delete(n.listeners, id)
+	}
+}
+
+func (n *notifier) record(resource string) {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if len(n.listeners) == 0 {
+		return
+	}
+	n.events = append(n.events, resource)
+}
+
+// flush delivers the recorded changes. When another goroutine or an outer call is
+// delivering already, it returns immediately and the changes are delivered by that one.
+func (n *notifier) flush() {
+	if n == nil {
+		return
+	}
+	n.mu.Lock()
+	if n.draining {
+		n.mu.Unlock()
+		return
+	}
+	n.draining = true
+	for len(n.events) > 0 {
+		resource := n.events[0]
+		n.events = n.events[1:]
+		var listeners []func(resource string)
+		for id := 0; id < n.nextID; id++ {
+			if f, ok := n.listeners[id]; ok {
+				listeners = append(listeners, f)
+			}
+		}
+		n.mu.Unlock()
+		for _, f := range listeners {
+			f(resource)
+		}
+		n.mu.Lock()
+	}
+	n.draining = false
+	n.mu.Unlock()
+}
+
+// OnChange registers the function called with the name of the resource, e.g. "items",
+// after the cache of the resource changes by local changes or syncs.
+// It is called outside of cache locks in the order of changes, possibly on another
+// goroutine changing caches at the same time. Call the returned function to unsubscribe.
+func (c *Client) OnChange(f func(resource string)) func() {
+	return c.notifier.subscribe(f)
+}
@@ -0,0 +1,44 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestClient_OnChange(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token", "projects": [{"id": 1, "name": "project"}]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	var events []string
+	unsubscribe := client.OnChange(func(resource string) {
+		events = append(events, resource)
+		// caches are accessible from listeners
+		client.Section.GetAll()
+		if resource == "sections" && len(events) == 1 {
+			label, _ := NewLabel("nested", &NewLabelOpts{})
+			client.Label.Add(*label)
+		}
+	})
+
+	section, _ := NewSection("section", &NewSectionOpts{})
+	client.Section.Add(*section)
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	expect := []string{"sections", "labels", "projects"}
+	if !reflect.DeepEqual(events, expect) {
+		t.Errorf("Expect %v, but got %v", expect, events)
+	}
+
+	unsubscribe()
+	events = nil
+	client.Section.Add(*section)
+	if len(events) != 0 {
+		t.Errorf("Expect no event after unsubscribe, but got %v", events)
+	}
+}
@@ -0,0 +1,207 @@
+package todoist
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// DefaultBaseURL is the endpoint used unless WithBaseURL is given.
+const DefaultBaseURL = "https://api.todoist.com/sync/v9"
+
+const defaultUserAgent = "go-todoist"
+
+// Endpoint is a family of the API endpoints, which may be served under another base URL than the sync.
+type Endpoint string
+
+const (
+	EndpointSync      Endpoint = "sync"
+	EndpointUpload    Endpoint = "upload"
+	EndpointCompleted Endpoint = "completed"
+	EndpointActivity  Endpoint = "activity"
+)
+
+// apiPath is a path of the API under the base URL of its endpoint family.
+type apiPath struct {
+	endpoint Endpoint
+	path     string
+}
+
+// The paths of the API, so that the methods resolve the base URL by the family of the path.
+var (
+	pathSync                = apiPath{EndpointSync, "sync"}
+	pathQuickAdd            = apiPath{EndpointSync, "quick/add"}
+	pathQuery               = apiPath{EndpointSync, "query"}
+	pathItemsGet            = apiPath{EndpointSync, "items/get"}
+	pathItemsGetCompleted   = apiPath{EndpointSync, "items/get_completed"}
+	pathProjectsGet         = apiPath{EndpointSync, "projects/get"}
+	pathProjectsGetData     = apiPath{EndpointSync, "projects/get_data"}
+	pathProjectsGetArchived = apiPath{EndpointSync, "projects/get_archived"}
+	pathSectionsGet         = apiPath{EndpointSync, "sections/get"}
+	pathLabelsGet           = apiPath{EndpointSync, "labels/get"}
+	pathFiltersGet          = apiPath{EndpointSync, "filters/get"}
+	pathBackupsGet          = apiPath{EndpointSync, "backups/get"}
+	pathEmailsGetOrCreate   = apiPath{EndpointSync, "emails/get_or_create"}
+	pathEmailsDisable       = apiPath{EndpointSync, "emails/disable"}
+	pathAccessTokensRevoke  = apiPath{EndpointSync, "access_tokens/revoke"}
+	pathUploadsAdd          = apiPath{EndpointUpload, "uploads/add"}
+	pathCompletedGetAll     = apiPath{EndpointCompleted, "completed/get_all"}
+	pathCompletedGetStats   = apiPath{EndpointCompleted, "completed/get_stats"}
+	pathActivityGet         = apiPath{EndpointActivity, "activity/get"}
+)
+
+// CacheMode is how the caches reflect the queued commands. See WithCacheMode.
+type CacheMode int
+
+const (
+	// CacheOptimistic changes the caches when commands are queued, so that GetAll reflects them at once.
+	// The changes are reverted by Discard, or when the commands are rejected on Commit.
+	CacheOptimistic CacheMode = iota
+	// CachePessimistic changes the caches only by the responses of the server, e.g. the sync of Commit,
+	// so that they hold the confirmed state only. Added resources are not resolved until they are committed.
+	CachePessimistic
+)
+
+type options struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	syncToken  string
+	cacheDir   string
+	logger     *log.Logger
+
+	strictDecoding bool
+	discardOnClose bool
+	resourceTypes  []string
+	requestHook    RequestHook
+	etagCacheSize  int
+	endpointURLs   map[Endpoint]string
+	requestTimeout time.Duration
+	cacheMode      CacheMode
+	retryPolicy    *RetryPolicy
+}
+
+// Option configures a client built by NewClient.
+type Option func(*options)
+
+// WithBaseURL sets the endpoint of the API, e.g. the URL of httptest.Server.
+func WithBaseURL(baseURL string) Option {
+	return func(o *options) {
+		o.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests, e.g. to set a timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets User-Agent header of requests.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithSyncToken sets the sync token to start from. It takes precedence over the cached one.
+func WithSyncToken(syncToken string) Option {
+	return func(o *options) {
+		o.syncToken = syncToken
+	}
+}
+
+// WithCacheDir sets the directory to store the cache. The default is $HOME/.go-todoist.
+func WithCacheDir(cacheDir string) Option {
+	return func(o *options) {
+		o.cacheDir = cacheDir
+	}
+}
+
+// WithLogger sets the logger of the client. Logs are discarded by default.
+func WithLogger(logger *log.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithStrictDecoding rejects responses with unknown fields, e.g. to catch changes of the API in tests.
+// By default, unknown fields are ignored since the API adds fields over time.
+func WithStrictDecoding() Option {
+	return func(o *options) {
+		o.strictDecoding = true
+	}
+}
+
+// WithDiscardOnClose makes Close discard the queued commands instead of committing them.
+func WithDiscardOnClose() Option {
+	return func(o *options) {
+		o.discardOnClose = true
+	}
+}
+
+// WithResourceTypes limits the resources fetched by syncs, e.g. "projects" and "sections".
+// Only the caches of the resources are updated. The default is "all".
+// The caches are persisted apart from the clients with other resource types in the same cache directory.
+func WithResourceTypes(resourceTypes ...string) Option {
+	return func(o *options) {
+		o.resourceTypes = resourceTypes
+	}
+}
+
+// WithRequestHook sets the function called after each request, e.g. to log requests.
+// No hook is called by default.
+func WithRequestHook(hook RequestHook) Option {
+	return func(o *options) {
+		o.requestHook = hook
+	}
+}
+
+// WithETagCache makes the read-only GETs of items and sections send If-None-Match,
+// and reuse the previous response on 304 Not Modified. At most size responses are kept,
+// or 128 if size is not positive. Responses are not cached by default.
+func WithETagCache(size int) Option {
+	return func(o *options) {
+		o.etagCacheSize = size
+		if size <= 0 {
+			o.etagCacheSize = defaultETagCacheSize
+		}
+	}
+}
+
+// WithEndpointURL sets the base URL of the endpoint family, e.g. to point uploads at a mock in tests.
+// The families without it are served under the base URL by WithBaseURL.
+func WithEndpointURL(endpoint Endpoint, baseURL string) Option {
+	return func(o *options) {
+		if o.endpointURLs == nil {
+			o.endpointURLs = map[Endpoint]string{}
+		}
+		o.endpointURLs[endpoint] = baseURL
+	}
+}
+
+// WithRequestTimeout sets the timeout of requests whose context has no deadline,
+// including the retries and reading the response. A deadline of the context is kept as is.
+// There is no timeout by default.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.requestTimeout = d
+	}
+}
+
+// WithRetryPolicy sets how requests are retried. nil disables retries. DefaultRetryPolicy is used by default.
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+// WithCacheMode sets whether queued commands, e.g. by Add, UpdateFields or ItemClient.Move, change the caches
+// before the commit. It is CacheOptimistic by default, which is responsive but shows changes the server may reject.
+// CachePessimistic never shows unconfirmed changes, but costs the sync of Commit to see them.
+func WithCacheMode(mode CacheMode) Option {
+	return func(o *options) {
+		o.cacheMode = mode
+	}
+}
@@ -0,0 +1,406 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewClient_Options(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var userAgent, syncToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		syncToken = r.FormValue("sync_token")
+		w.Write([]byte(`{"sync_token": "next-token"}`))
+	}))
+	defer server.Close()
+
+	httpClient := &http.Client{Timeout: time.Second}
+	client, err := NewClient("test-token",
+		WithBaseURL(server.URL),
+		WithCacheDir(dir),
+		WithHTTPClient(httpClient),
+		WithUserAgent("my-app/1.0"),
+		WithSyncToken("token"),
+		WithRetryPolicy(nil))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if client.HTTPClient != httpClient {
+		t.Error("Expect the given http client")
+	}
+	if client.RetryPolicy != nil {
+		t.Errorf("Expect retries to be disabled, but got %v", client.RetryPolicy)
+	}
+	if err = client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if userAgent != "my-app/1.0" {
+		t.Errorf("Expect %s, but got %s", "my-app/1.0", userAgent)
+	}
+	if syncToken != "token" {
+		t.Errorf("Expect %s, but got %s", "token", syncToken)
+	}
+}
+
+func TestNewClient_Defaults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	client, err := NewClient("test-token", WithCacheDir(dir))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if client.URL.String() != DefaultBaseURL {
+		t.Errorf("Expect %s, but got %s", DefaultBaseURL, client.URL)
+	}
+	if client.SyncToken() != "*" {
+		t.Errorf("Expect %s, but got %s", "*", client.SyncToken())
+	}
+	if client.RetryPolicy != DefaultRetryPolicy {
+		t.Errorf("Expect %v, but got %v", DefaultRetryPolicy, client.RetryPolicy)
+	}
+	if _, err = NewClient("", WithCacheDir(dir)); err == nil {
+		t.Error("Expect error for missing token, but got nil")
+	}
+	if _, err = NewClient("test-token", WithBaseURL("not a url")); err == nil {
+		t.Error("Expect error for invalid base url, but got nil")
+	}
+}
+
+func TestNewClient_StrictDecoding(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token", "new_field": 1}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.RetryPolicy = nil
+	if err = client.Sync(context.Background(), []Command{}); err != nil {
+		t.Errorf("Expect unknown fields to be ignored, but got %s", err)
+	}
+
+	strict, err := NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir), WithStrictDecoding())
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	strict.RetryPolicy = nil
+	if err = strict.Sync(context.Background(), []Command{}); err == nil || !strings.Contains(err.Error(), "new_field") {
+		t.Errorf("Expect error for unknown field, but got %v", err)
+	}
+}
+
+func TestClient_DecodeBodyError(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<html><body>Internal Server Error</body></html>`))
+	})
+	defer teardown()
+
+	err := client.Sync(context.Background(), []Command{})
+	if err == nil || !strings.Contains(err.Error(), "Internal Server Error") {
+		t.Errorf("Expect error with the body, but got %v", err)
+	}
+}
+
+func TestNewClient_ResourceTypes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var resourceTypes []string
+	var syncToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		syncToken = r.FormValue("sync_token")
+		resourceTypes = nil
+		if err := json.Unmarshal([]byte(r.FormValue("resource_types")), &resourceTypes); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		w.Write([]byte(`{"sync_token": "next-token", "full_sync": true, "sections": [{"id": 1, "name": "Todo"}]}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir), WithResourceTypes("sections", "projects"))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.RetryPolicy = nil
+	item := Item{Content: "keep"}
+	item.ID = "2"
+	client.Item.cache.store(item)
+	if err = client.FullSync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := []string{"sections", "projects"}; !reflect.DeepEqual(resourceTypes, expect) {
+		t.Errorf("Expect %v, but got %v", expect, resourceTypes)
+	}
+	if client.Section.Resolve("1") == nil {
+		t.Error("Expect section to be cached")
+	}
+	if client.Item.Resolve("2") == nil {
+		t.Error("Expect items not to be replaced")
+	}
+
+	client, err = NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.RetryPolicy = nil
+	if err = client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := []string{"all"}; !reflect.DeepEqual(resourceTypes, expect) {
+		t.Errorf("Expect %v, but got %v", expect, resourceTypes)
+	}
+	// the sync token of the limited client does not cover the other resources.
+	if syncToken != "*" {
+		t.Errorf("Expect %s, but got %s", "*", syncToken)
+	}
+}
+
+func TestNewClient_RequestHook(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token"}`))
+	}))
+	defer server.Close()
+
+	var infos []RequestInfo
+	client, err := NewClient("secret-token", WithBaseURL(server.URL), WithCacheDir(dir), WithRequestHook(func(info RequestInfo) {
+		infos = append(infos, info)
+	}))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.RetryPolicy = nil
+	client.Project.Archive("1")
+	client.Project.Archive("2")
+	if err = client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.Project.GetArchived(context.Background())
+	client.Backup.Download(context.Background(), Backup{URL: server.URL + "/backup.zip"}, ioutil.Discard)
+
+	if len(infos) != 3 {
+		t.Fatalf("Expect %d requests, but got %d", 3, len(infos))
+	}
+	if info := infos[0]; info.Method != http.MethodPost || info.Commands != 2 || info.StatusCode != http.StatusOK || info.Attempt != 1 {
+		t.Errorf("Unexpect info: %v", info)
+	}
+	for _, info := range infos {
+		if strings.Contains(info.URL, "secret-token") || strings.Contains(info.Header.Get("Authorization"), "secret-token") {
+			t.Errorf("Expect token to be redacted, but got %v", info)
+		}
+	}
+	if info := infos[2]; info.Header.Get("Authorization") != "REDACTED" {
+		t.Errorf("Expect %s, but got %s", "REDACTED", info.Header.Get("Authorization"))
+	}
+
+	server.Close()
+	client.Project.GetArchived(context.Background())
+	if info := infos[len(infos)-1]; info.Err == nil || strings.Contains(info.Err.Error(), "secret-token") {
+		t.Errorf("Expect the error with the token redacted, but got %v", info.Err)
+	}
+}
+
+func TestNewClient_EndpointURL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	var syncPaths, uploadPaths []string
+	syncServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		syncPaths = append(syncPaths, r.URL.Path)
+		w.Write([]byte(`{"sync_token": "next-token", "items": []}`))
+	}))
+	defer syncServer.Close()
+	uploadServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploadPaths = append(uploadPaths, r.URL.Path)
+		w.Write([]byte(`{"file_name": "a.txt", "file_url": "https://example.com/a.txt"}`))
+	}))
+	defer uploadServer.Close()
+
+	if _, err = NewClient("test-token", WithEndpointURL(EndpointUpload, "invalid")); err == nil {
+		t.Error("Expect error for invalid url, but got nil")
+	}
+	client, err := NewClient("test-token", WithBaseURL(syncServer.URL), WithCacheDir(dir),
+		WithEndpointURL(EndpointUpload, uploadServer.URL+"/v1"))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.RetryPolicy = nil
+	if _, err = client.Note.Upload(context.Background(), strings.NewReader("content"), "a.txt"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if _, err = client.Completed.GetCompleted(context.Background(), CompletedOpts{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := []string{"/v1/uploads/add"}; !reflect.DeepEqual(uploadPaths, expect) {
+		t.Errorf("Expect %v, but got %v", expect, uploadPaths)
+	}
+	if expect := []string{"/completed/get_all"}; !reflect.DeepEqual(syncPaths, expect) {
+		t.Errorf("Expect %v, but got %v", expect, syncPaths)
+	}
+}
+
+func TestNewClient_RequestTimeout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	done := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+		}
+		w.Write([]byte(`{"sync_token": "next-token"}`))
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir), WithRequestTimeout(50*time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.RetryPolicy = nil
+	client.SetSyncToken("token")
+	start := time.Now()
+	if err = client.Sync(context.Background(), []Command{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expect %s, but got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expect the request to abort after the timeout, but took %s", elapsed)
+	}
+
+	// a shorter deadline of the context is kept.
+	client, err = NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir), WithRequestTimeout(time.Hour))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.RetryPolicy = nil
+	client.SetSyncToken("token")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start = time.Now()
+	if err = client.Sync(ctx, []Command{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expect %s, but got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expect the request to abort by the deadline, but took %s", elapsed)
+	}
+}
+
+func TestNewClient_CacheMode(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-todoist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		res := map[string]interface{}{"sync_token": "next-token"}
+		if len(commands) != 0 {
+			res["temp_id_mapping"] = map[ID]int{commands[0].TempID: 100}
+			res["items"] = []map[string]interface{}{{"id": 100, "content": "added"}}
+			res["sections"] = []map[string]interface{}{{"id": 1, "name": "renamed"}}
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer server.Close()
+
+	client, err := NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir), WithCacheMode(CachePessimistic))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.RetryPolicy = nil
+	client.SetSyncToken("token")
+	client.Section.cache.store(Section{Entity: Entity{ID: "1"}, Name: "section"})
+
+	item, _ := NewItem("added", nil)
+	client.Item.Add(*item)
+	client.Section.UpdateFields("1", map[string]interface{}{"name": "renamed"})
+	if len(client.Item.GetAll()) != 0 || client.Section.Resolve("1").Name != "section" {
+		t.Errorf("Expect the caches not to be changed before the commit, but got %v, %v", client.Item.GetAll(), client.Section.GetAll())
+	}
+	if err = client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if item := client.Item.Resolve("100"); item == nil || item.Content != "added" {
+		t.Errorf("Expect the committed item, but got %v", item)
+	}
+	if section := client.Section.Resolve("1"); section.Name != "renamed" {
+		t.Errorf("Expect %s, but got %s", "renamed", section.Name)
+	}
+	client.Project.cache.store(Project{Entity: Entity{ID: "10"}, Name: "project"})
+	client.Note.cache.store(Note{Entity: Entity{ID: "20"}, Content: "note"})
+	client.Item.Move("100", &ItemMoveOpts{ProjectID: "10"})
+	client.Item.Complete("100", Time{}, false)
+	client.Item.AddLabel("100", "30")
+	client.Item.ReorderDay([]ItemDayOrder{{ID: "100", DayOrder: 5}})
+	client.Project.Archive("10")
+	client.Note.Edit("20", "edited")
+	if item := client.Item.Resolve("100"); item.ProjectID != "" || item.Checked || len(item.Labels) != 0 || item.DayOrder != 0 {
+		t.Errorf("Expect the item not to be changed before the commit, but got %v", item)
+	}
+	if project := client.Project.Resolve("10"); project.IsArchived {
+		t.Errorf("Expect the project not to be changed before the commit, but got %v", project)
+	}
+	if note := client.Note.Resolve("20"); note.Content != "note" {
+		t.Errorf("Expect the note not to be changed before the commit, but got %v", note)
+	}
+	client.Discard()
+
+	// optimistic writes are reverted by Discard, even after a sync.
+	client, err = NewClient("test-token", WithBaseURL(server.URL), WithCacheDir(dir))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	synced := client.Section.Resolve("1")
+	item, _ = NewItem("discarded", nil)
+	client.Item.Add(*item)
+	client.Section.UpdateFields("1", map[string]interface{}{"name": "discarded"})
+	client.Section.Delete("1")
+	if client.Item.Resolve(item.ID) == nil || client.Section.Resolve("1") != nil {
+		t.Fatalf("Expect the caches to be changed, but got %v, %v", client.Item.GetAll(), client.Section.GetAll())
+	}
+	if err = client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	client.Discard()
+	if client.Item.Resolve(item.ID) != nil || !reflect.DeepEqual(client.Section.Resolve("1"), synced) {
+		t.Errorf("Expect the changes to be reverted, but got %v, %v", client.Item.GetAll(), client.Section.GetAll())
+	}
+}
@@ -0,0 +1,99 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// pollMaxFailures is the number of consecutive failures of Poll before the error is reported.
+	pollMaxFailures = 3
+	// pollMaxBackoff is the maximum multiple of the interval Poll waits after failures.
+	pollMaxBackoff = 16
+)
+
+// Poll syncs the caches every interval until ctx is done, and calls onUpdate with the resources changed
+// by the sync, e.g. "items", as OnChange names them. onUpdate is not called for syncs without changes.
+// Queued commands are not sent. The first sync starts immediately.
+//
+// Failed syncs are retried after a longer wait, doubled up to 16 intervals, or after Retry-After
+// when rate limited. Errors are sent to the returned channel only when syncs fail 3 times in a row,
+// and the polling goes on. The channel is closed when the polling stops. Errors are dropped
+// while the channel has an unread one, so that an idle reader does not block the polling.
+func (c *Client) Poll(ctx context.Context, interval time.Duration, onUpdate func(changed []string)) <-chan error {
+	errs := make(chan error, 1)
+	go func() {
+		defer close(errs)
+		failures := 0
+		for {
+			wait := interval
+			c.syncMu.Lock()
+			state, err := c.sync(ctx, []Command{})
+			c.syncMu.Unlock()
+			switch {
+			case ctx.Err() != nil:
+				return
+			case err != nil:
+				failures++
+				wait = interval * time.Duration(backoffMultiple(failures))
+				var rateLimitErr *RateLimitError
+				if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > wait {
+					wait = rateLimitErr.RetryAfter
+				}
+				if failures >= pollMaxFailures {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			default:
+				failures = 0
+				if changed := changedResources(state); len(changed) != 0 && onUpdate != nil {
+					onUpdate(changed)
+				}
+			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+	}()
+	return errs
+}
+
+// backoffMultiple returns the multiple of the interval to wait after the consecutive failures.
+func backoffMultiple(failures int) int {
+	n := 1
+	for i := 0; i < failures && n < pollMaxBackoff; i++ {
+		n *= 2
+	}
+	return n
+}
+
+// changedResources returns the names of the resources in the sync response.
+func changedResources(state *SyncState) []string {
+	var res []string
+	for _, r := range []struct {
+		name    string
+		changed bool
+	}{
+		{"user", state.User != nil},
+		{"projects", len(state.Projects) != 0},
+		{"sections", len(state.Sections) != 0},
+		{"items", len(state.Items) != 0},
+		{"notes", len(state.Notes) != 0 || len(state.ProjectNotes) != 0},
+		{"labels", len(state.Labels) != 0},
+		{"filters", len(state.Filters) != 0},
+		{"reminders", len(state.Reminders) != 0},
+		{"collaborators", len(state.Collaborators) != 0 || len(state.CollaboratorStates) != 0},
+	} {
+		if r.changed {
+			res = append(res, r.name)
+		}
+	}
+	return res
+}
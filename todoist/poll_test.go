@@ -0,0 +1,86 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_Poll(t *testing.T) {
+	var mu sync.Mutex
+	requests := 0
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests++
+		n := requests
+		mu.Unlock()
+		switch {
+		case n == 1:
+			w.Write([]byte(`{"sync_token": "token-1", "items": [{"id": 1, "content": "item"}], "project_notes": [{"id": 2, "project_id": 3}]}`))
+		case n == 2:
+			w.Write([]byte(`{"sync_token": "token-2"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	var updates [][]string
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := client.Poll(ctx, 5*time.Millisecond, func(changed []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		updates = append(updates, changed)
+	})
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("Expect error, but got nil")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expect error after the consecutive failures, but got nothing")
+	}
+	mu.Lock()
+	if requests < 2+pollMaxFailures {
+		t.Errorf("Expect the error after %d failures, but got %d requests", pollMaxFailures, requests)
+	}
+	if expect := [][]string{{"items", "notes"}}; !reflect.DeepEqual(updates, expect) {
+		t.Errorf("Expect %v, but got %v", expect, updates)
+	}
+	mu.Unlock()
+	if client.Item.Resolve("1") == nil {
+		t.Error("Expect the item to be cached")
+	}
+
+	cancel()
+	select {
+	case <-closed(errs):
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expect the channel to be closed after cancel")
+	}
+}
+
+// closed returns a channel closed when errs is closed, discarding errors.
+func closed(errs <-chan error) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		for range errs {
+		}
+		close(done)
+	}()
+	return done
+}
+
+func TestBackoffMultiple(t *testing.T) {
+	for failures, expect := range []int{1, 2, 4, 8, 16, 16, 16} {
+		if n := backoffMultiple(failures); n != expect {
+			t.Errorf("Expect %d for %d failures, but got %d", expect, failures, n)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package todoist
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Priority is a priority of items.
+// Note that the API and the official apps number priorities in reverse order:
+// p1, the most urgent priority in the apps, is 4 in the API, and p4 is 1.
+type Priority int
+
+const (
+	P1 Priority = 4
+	P2 Priority = 3
+	P3 Priority = 2
+	P4 Priority = 1
+)
+
+func (p Priority) IsValid() bool {
+	return P4 <= p && p <= P1
+}
+
+// Label returns the priority as shown in the official apps, e.g. "p1" for P1.
+func (p Priority) Label() string {
+	if !p.IsValid() {
+		return strconv.Itoa(int(p))
+	}
+	return fmt.Sprintf("p%d", 5-int(p))
+}
+
+// SetPriority updates the priority of the item.
+func (c *ItemClient) SetPriority(id ID, p Priority) error {
+	if !p.IsValid() {
+		return fmt.Errorf("invalid priority: %d", p)
+	}
+	c.enqueue("item_update", map[string]interface{}{
+		"id":       id,
+		"priority": p,
+	}, "")
+	if item := c.Resolve(id); item != nil && c.optimistic() {
+		item.Priority = p
+		c.cache.store(*item)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPriority_Label(t *testing.T) {
+	for p, expect := range map[Priority]string{P1: "p1", P2: "p2", P3: "p3", P4: "p4", 0: "0"} {
+		if label := p.Label(); label != expect {
+			t.Errorf("Expect %s, but got %s", expect, label)
+		}
+	}
+}
+
+func TestItemClient_SetPriority(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+
+	if err := client.Item.SetPriority("1", P1); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.SetPriority("1", 5); err == nil {
+		t.Error("Expect error for invalid priority, but got nil")
+	}
+	if len(client.queue) != 1 || client.queue[0].Type != "item_update" {
+		t.Fatalf("Unexpect queue: %v", client.queue)
+	}
+	b, err := json.Marshal(client.queue[0].Args)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := `{"id":"1","priority":4}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+}
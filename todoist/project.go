@@ -3,28 +3,48 @@ package todoist
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/fatih/color"
 	"net/http"
 	"net/url"
+	"regexp"
+	"sort"
 	"strings"
+	"sync"
 )
 
 type Project struct {
 	Entity
-	Name         string  `json:"name"`
-	Color        int     `json:"color"`
-	ChildOrder   int     `json:"child_order"`
-	ParentID     ID      `json:"parent_id"`
-	Collapsed    IntBool `json:"collapsed"`
-	Shared       bool    `json:"shared"`
-	IsArchived   IntBool `json:"is_archived"`
-	IsFavorite   IntBool `json:"is_favorite"`
-	InboxProject bool    `json:"inbox_project"`
-	TeamInbox    bool    `json:"team_inbox"`
+	Name         string    `json:"name"`
+	Color        Color     `json:"color"`
+	ChildOrder   int       `json:"child_order"`
+	ParentID     ID        `json:"parent_id"`
+	Collapsed    IntBool   `json:"collapsed"`
+	Shared       bool      `json:"shared"`
+	IsArchived   IntBool   `json:"is_archived"`
+	IsFavorite   IntBool   `json:"is_favorite"`
+	InboxProject bool      `json:"inbox_project"`
+	TeamInbox    bool      `json:"team_inbox"`
+	ViewStyle    ViewStyle `json:"view_style,omitempty"`
+}
+
+// ViewStyle is how the project is shown in the official apps.
+// In the board style, the sections of the project are the columns of the board,
+// and items without section are in the first column.
+type ViewStyle string
+
+const (
+	ViewStyleList  ViewStyle = "list"
+	ViewStyleBoard ViewStyle = "board"
+)
+
+// IsValid reports whether the style is known.
+func (s ViewStyle) IsValid() bool {
+	return s == ViewStyleList || s == ViewStyleBoard
 }
 
 type NewProjectOpts struct {
-	Color      int
+	Color      Color
 	ParentID   ID
 	ChildOrder int
 	IsFavorite IntBool
@@ -34,6 +54,9 @@ func NewProject(name string, opts *NewProjectOpts) (*Project, error) {
 	if len(name) == 0 {
 		return nil, errors.New("new project requires a name")
 	}
+	if opts == nil {
+		opts = &NewProjectOpts{}
+	}
 	project := Project{
 		Name:       name,
 		ParentID:   opts.ParentID,
@@ -42,7 +65,7 @@ func NewProject(name string, opts *NewProjectOpts) (*Project, error) {
 	}
 	project.ID = GenerateTempID()
 	if opts.Color == 0 {
-		project.Color = 47
+		project.Color = Charcoal
 	} else {
 		project.Color = opts.Color
 	}
@@ -54,26 +77,7 @@ func (p Project) String() string {
 }
 
 func (p Project) ColorString() string {
-	var attr color.Attribute
-	switch p.Color {
-	case 30, 31:
-		attr = color.FgHiRed
-	case 32, 33:
-		attr = color.FgHiYellow
-	case 34, 35, 36:
-		attr = color.FgHiGreen
-	case 37, 38, 39:
-		attr = color.FgHiCyan
-	case 40, 41, 42:
-		attr = color.FgHiBlue
-	case 43, 44, 45, 46:
-		attr = color.FgHiMagenta
-	case 47, 48, 49:
-		attr = color.FgHiBlack
-	default:
-		attr = color.FgWhite
-	}
-	return color.New(attr).Sprint(p.String())
+	return color.New(p.Color.attribute()).Sprint(p.String())
 }
 
 type ProjectClient struct {
@@ -82,89 +86,157 @@ type ProjectClient struct {
 }
 
 func (c *ProjectClient) Add(project Project) (*Project, error) {
-	c.cache.store(project)
-	command := Command{
-		Type:   "project_add",
-		Args:   project,
-		UUID:   GenerateUUID(),
-		TempID: project.ID,
-	}
-	c.queue = append(c.queue, command)
+	if c.optimistic() {
+		c.cache.store(project)
+	}
+	c.enqueue("project_add", project, project.ID)
 	return &project, nil
 }
 
 func (c *ProjectClient) Update(project Project) (*Project, error) {
-	command := Command{
-		Type: "project_update",
-		Args: project,
-		UUID: GenerateUUID(),
+	if err := validateName("project", project.Name); err != nil {
+		return nil, err
 	}
-	c.queue = append(c.queue, command)
+	c.enqueue("project_update", project, "")
 	return &project, nil
 }
 
+// projectUpdateFields are the fields UpdateFields accepts. Use Move to change parent_id.
+var projectUpdateFields = []string{"name", "color", "collapsed", "is_favorite", "view_style"}
+
+// UpdateFields updates only the given fields of the project, e.g. {"name": "Work"},
+// so that the other fields are not cleared by zero values. Accepted fields are
+// "name", "color" (Color), "collapsed" and "is_favorite" (IntBool), and "view_style" (ViewStyle).
+// The cached project is rolled back if the command fails on Commit.
+func (c *ProjectClient) UpdateFields(id ID, fields map[string]interface{}) error {
+	args, err := updateArgs(id, fields, projectUpdateFields)
+	if err != nil {
+		return err
+	}
+	if err = validateNameField("project", fields); err != nil {
+		return err
+	}
+	if project := c.Resolve(id); project != nil && c.optimistic() {
+		if err = mergeFields(project, fields); err != nil {
+			return err
+		}
+		c.cache.markPending(id)
+		c.cache.store(*project)
+	}
+	c.enqueue("project_update", args, "")
+	return nil
+}
+
+// SetCollapsed collapses or expands the sub-projects of the project, as the official apps show them.
+func (c *ProjectClient) SetCollapsed(id ID, collapsed bool) error {
+	return c.UpdateFields(id, map[string]interface{}{"collapsed": IntBool(collapsed)})
+}
+
 func (c *ProjectClient) Move(id, parentID ID) error {
-	command := Command{
-		Type: "project_move",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id":        id,
-			"parent_id": parentID,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("project_move", map[string]ID{
+		"id":        id,
+		"parent_id": parentID,
+	}, "")
 	return nil
 
 }
 
+func (c *ProjectClient) SetColor(id ID, color Color) error {
+	if !color.IsValid() {
+		return fmt.Errorf("invalid color: %s", color)
+	}
+	c.enqueue("project_update", map[string]interface{}{
+		"id":    id,
+		"color": color,
+	}, "")
+	return nil
+}
+
+// SetViewStyle shows the project as a list or a board. See ViewStyle.
+func (c *ProjectClient) SetViewStyle(id ID, style ViewStyle) error {
+	if !style.IsValid() {
+		return fmt.Errorf("invalid view style: %s", style)
+	}
+	return c.UpdateFields(id, map[string]interface{}{"view_style": style})
+}
+
 func (c *ProjectClient) Delete(id ID) error {
-	command := Command{
-		Type: "project_delete",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("project_delete", map[string]ID{"id": id}, "")
 	return nil
 }
 
+// DeleteIfExists deletes the project only if it is cached. See SectionClient.DeleteIfExists.
+func (c *ProjectClient) DeleteIfExists(id ID, opts *DeleteOpts) error {
+	if c.Resolve(id) == nil {
+		return opts.notFound("project", id)
+	}
+	return c.Delete(id)
+}
+
+// Archive archives the project and its descendants, and marks them as archived in the cache.
 func (c *ProjectClient) Archive(id ID) error {
-	command := Command{
-		Type: "project_archive",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("project_archive", map[string]ID{"id": id}, "")
+	c.setArchived(id, true)
+	var archive func(id ID, seen map[ID]bool)
+	archive = func(id ID, seen map[ID]bool) {
+		for _, child := range c.Children(id) {
+			if seen[child.ID] {
+				continue
+			}
+			seen[child.ID] = true
+			c.setArchived(child.ID, true)
+			archive(child.ID, seen)
+		}
+	}
+	archive(id, map[ID]bool{id: true})
 	return nil
 }
 
+// Unarchive unarchives the project, and marks it as unarchived in the cache.
+// Neither its ancestors nor its descendants are unarchived.
 func (c *ProjectClient) Unarchive(id ID) error {
-	command := Command{
-		Type: "project_unarchive",
-		UUID: GenerateUUID(),
-		Args: map[string]ID{
-			"id": id,
-		},
-	}
-	c.queue = append(c.queue, command)
+	c.enqueue("project_unarchive", map[string]ID{"id": id}, "")
+	c.setArchived(id, false)
 	return nil
 }
 
-func (c *ProjectClient) Reorder(projects []Project) error {
-	command := Command{
-		Type: "project_reorder",
-		UUID: GenerateUUID(),
-		Args: map[string][]Project{
-			"projects": projects,
-		},
+func (c *ProjectClient) setArchived(id ID, archived bool) {
+	if project := c.Resolve(id); project != nil && c.optimistic() {
+		project.IsArchived = IntBool(archived)
+		c.cache.store(*project)
+	}
+}
+
+// ProjectOrder is a pair of a project and its order among the siblings.
+type ProjectOrder struct {
+	ID         ID  `json:"id"`
+	ChildOrder int `json:"child_order"`
+}
+
+// Reorder updates the orders of the sibling projects, and the child orders of the cached projects.
+func (c *ProjectClient) Reorder(projects []ProjectOrder) error {
+	if len(projects) == 0 {
+		return errors.New("reorder requires projects")
+	}
+	c.enqueue("project_reorder", map[string][]ProjectOrder{"projects": projects}, "")
+	for _, order := range projects {
+		if project := c.Resolve(order.ID); project != nil && c.optimistic() {
+			project.ChildOrder = order.ChildOrder
+			c.cache.store(*project)
+		}
 	}
-	c.queue = append(c.queue, command)
 	return nil
 }
 
+// ReorderIDs reorders the sibling projects along the given ids, numbering child orders from 1.
+func (c *ProjectClient) ReorderIDs(ids []ID) error {
+	projects := make([]ProjectOrder, len(ids))
+	for i, id := range ids {
+		projects[i] = ProjectOrder{ID: id, ChildOrder: i + 1}
+	}
+	return c.Reorder(projects)
+}
+
 type ProjectGetResponse struct {
 	Project Project
 	Notes   []Note
@@ -172,57 +244,76 @@ type ProjectGetResponse struct {
 
 func (c *ProjectClient) Get(ctx context.Context, id ID) (*ProjectGetResponse, error) {
 	values := url.Values{"project_id": {id.String()}}
-	req, err := c.newRequest(ctx, http.MethodGet, "projects/get", values)
+	req, err := c.newRequest(ctx, http.MethodGet, pathProjectsGet, values)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	var out ProjectGetResponse
-	err = decodeBody(res, &out)
+	err = c.decodeBody(res, &out)
 	if err != nil {
 		return nil, err
 	}
 	return &out, nil
 }
 
-type ProjectGetDataResponse struct {
-	Project Project
-	Items   []Item
+// ProjectData is a project with its sections, items and notes, e.g. to render a project view.
+type ProjectData struct {
+	Project  Project   `json:"project"`
+	Sections []Section `json:"sections"`
+	Items    []Item    `json:"items"`
+	Notes    []Note    `json:"project_notes"`
 }
 
-func (c *ProjectClient) GetData(ctx context.Context, id ID) (*ProjectGetDataResponse, error) {
+// GetData fetches the project with its sections, items and notes in a request, and stores them in the caches.
+// It returns *NotFoundError if the project does not exist.
+func (c *ProjectClient) GetData(ctx context.Context, id ID) (*ProjectData, error) {
 	values := url.Values{"project_id": {id.String()}}
-	req, err := c.newRequest(ctx, http.MethodGet, "projects/get_data", values)
+	req, err := c.newRequest(ctx, http.MethodGet, pathProjectsGetData, values)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
-	var out ProjectGetDataResponse
-	err = decodeBody(res, &out)
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, &NotFoundError{Resource: "project", ID: id}
+	}
+	var out ProjectData
+	err = c.decodeBody(res, &out)
 	if err != nil {
 		return nil, err
 	}
+	c.cache.store(out.Project)
+	for _, section := range out.Sections {
+		c.Section.cache.store(section)
+	}
+	for _, item := range out.Items {
+		c.Item.cache.store(item)
+	}
+	for _, note := range out.Notes {
+		c.Note.cache.store(note)
+	}
 	return &out, nil
 }
 
 func (c *ProjectClient) GetArchived(ctx context.Context) (*[]Project, error) {
 	values := url.Values{}
-	req, err := c.newRequest(ctx, http.MethodGet, "projects/get_archived", values)
+	req, err := c.newRequest(ctx, http.MethodGet, pathProjectsGetArchived, values)
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
 	var out []Project
-	err = decodeBody(res, &out)
+	err = c.decodeBody(res, &out)
 	if err != nil {
 		return nil, err
 	}
@@ -233,6 +324,30 @@ func (c *ProjectClient) GetAll() []Project {
 	return c.cache.getAll()
 }
 
+// GetActive returns the cached projects which are not archived.
+func (c *ProjectClient) GetActive() []Project {
+	var res []Project
+	for _, project := range c.GetAll() {
+		if !project.IsArchived.Bool() {
+			res = append(res, project)
+		}
+	}
+	return res
+}
+
+// GetAllArchived returns the cached projects which are archived.
+// GetArchived fetches them from the server instead.
+func (c *ProjectClient) GetAllArchived() []Project {
+	var res []Project
+	for _, project := range c.GetAll() {
+		if project.IsArchived.Bool() {
+			res = append(res, project)
+		}
+	}
+	return res
+}
+
+// Resolve returns a copy of the cached project. Use Update to modify it.
 func (c *ProjectClient) Resolve(id ID) *Project {
 	return c.cache.resolve(id)
 }
@@ -241,13 +356,22 @@ func (c ProjectClient) FindByName(substr string) []Project {
 	if r := []rune(substr); len(r) > 0 && string(r[0]) == "#" {
 		substr = string(r[1:])
 	}
+	return c.cache.findByName(substr)
+}
+
+// FindByNameRegex returns the cached projects whose name matches the regular expression.
+func (c ProjectClient) FindByNameRegex(pattern string) ([]Project, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
 	var res []Project
 	for _, p := range c.GetAll() {
-		if strings.Contains(p.Name, substr) {
+		if re.MatchString(p.Name) {
 			res = append(res, p)
 		}
 	}
-	return res
+	return res, nil
 }
 
 func (c ProjectClient) FindOneByName(substr string) *Project {
@@ -263,48 +387,260 @@ func (c ProjectClient) FindOneByName(substr string) *Project {
 	return nil
 }
 
+// Children returns the cached projects whose parent is the given project, ordered by child order.
+func (c ProjectClient) Children(id ID) []Project {
+	var res []Project
+	for _, p := range c.GetAll() {
+		if p.ParentID == id && !id.IsZero() {
+			res = append(res, p)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].ChildOrder < res[j].ChildOrder
+	})
+	return res
+}
+
+// Parent returns the cached parent of the given project, or nil for a root project.
+func (c ProjectClient) Parent(id ID) *Project {
+	project := c.Resolve(id)
+	if project == nil || project.ParentID.IsZero() {
+		return nil
+	}
+	return c.Resolve(project.ParentID)
+}
+
+// Ancestors returns the cached ancestors of the given project, from its parent to the root.
+// It stops at a project visited already, so that a malformed hierarchy does not loop forever.
+func (c ProjectClient) Ancestors(id ID) []Project {
+	var res []Project
+	visited := map[ID]bool{id: true}
+	for parent := c.Parent(id); parent != nil && !visited[parent.ID]; parent = c.Parent(parent.ID) {
+		visited[parent.ID] = true
+		res = append(res, *parent)
+	}
+	return res
+}
+
+// Depth returns the number of ancestors of the given project. A root project is 0.
+func (c ProjectClient) Depth(id ID) int {
+	return len(c.Ancestors(id))
+}
+
 type projectCache struct {
 	cache *[]Project
+	// index maps ids to positions in cache.
+	index map[ID]int
+	// pending holds projects before local changes by id, rolled back if the changes are rejected.
+	pending map[ID]*Project
+	mu      sync.RWMutex
+	notify  *notifier
 }
 
 func (c *projectCache) getAll() []Project {
-	return *c.cache
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Project, len(*c.cache))
+	copy(res, *c.cache)
+	return res
 }
 
+// findByName returns copies of the cached projects whose name contains substr,
+// without copying the others.
+func (c *projectCache) findByName(substr string) []Project {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var res []Project
+	for _, p := range *c.cache {
+		if strings.Contains(p.Name, substr) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// resolve returns a copy of the cached project, so that it is safe to read
+// while the cache is updated. Modifying it does not affect the cache.
 func (c *projectCache) resolve(id ID) *Project {
-	for _, project := range *c.cache {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	i, ok := c.lookup(id)
+	if !ok {
+		return nil
+	}
+	res := (*c.cache)[i]
+	return &res
+}
+
+// lookup returns the position of the project in the cache. The caller must hold the lock.
+// The cache is scanned if it is not indexed yet.
+func (c *projectCache) lookup(id ID) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[id]
+		return i, ok
+	}
+	for i, project := range *c.cache {
 		if project.ID == id {
-			return &project
+			return i, true
 		}
 	}
-	return nil
+	return 0, false
+}
+
+// set swaps the cache for the projects and indexes them. The caller must hold the lock.
+func (c *projectCache) set(projects []Project) {
+	c.cache = &projects
+	c.index = indexProjects(projects)
+}
+
+func indexProjects(projects []Project) map[ID]int {
+	res := make(map[ID]int, len(projects))
+	for i, project := range projects {
+		res[project.ID] = i
+	}
+	return res
 }
 
 func (c *projectCache) store(project Project) {
-	var res []Project
-	isNew := true
-	for _, p := range *c.cache {
-		if p.Equal(project) {
-			if !project.IsDeleted {
-				res = append(res, project)
-			}
-			isNew = false
-		} else {
-			res = append(res, p)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("projects")
+	i, ok := c.lookup(project.ID)
+	switch {
+	case project.IsDeleted.Bool():
+		if ok {
+			c.removeAt(i)
+		}
+	case ok:
+		(*c.cache)[i] = project
+	default:
+		*c.cache = append(*c.cache, project)
+		if c.index != nil {
+			c.index[project.ID] = len(*c.cache) - 1
 		}
 	}
-	if isNew && !project.IsDeleted.Bool() {
-		res = append(res, project)
-	}
-	c.cache = &res
 }
 
 func (c *projectCache) remove(project Project) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("projects")
+	if i, ok := c.lookup(project.ID); ok {
+		c.removeAt(i)
+	}
+}
+
+// removeAt removes the project at the position, and shifts the positions of the following ones in the index.
+// The caller must hold the lock.
+func (c *projectCache) removeAt(i int) {
+	projects := *c.cache
+	id := projects[i].ID
+	copy(projects[i:], projects[i+1:])
+	projects = projects[:len(projects)-1]
+	*c.cache = projects
+	if c.index != nil {
+		delete(c.index, id)
+		for j := i; j < len(projects); j++ {
+			c.index[projects[j].ID] = j
+		}
+	}
+}
+
+func (c *projectCache) replaceTempIDs(mapping map[ID]ID) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("projects")
+	res := make([]Project, len(*c.cache))
+	copy(res, *c.cache)
+	for i, project := range res {
+		if id, ok := mapping[project.ID]; ok {
+			res[i].ID = id
+		}
+		res[i].ParentID = mapTempID(project.ParentID, mapping)
+	}
+	c.set(res)
+}
+
+// replace swaps the cache for the given projects at once.
+// Cached projects with temporary ids are kept, since they are not committed yet.
+func (c *projectCache) replace(projects []Project) {
 	var res []Project
-	for _, p := range *c.cache {
-		if !p.Equal(project) {
-			res = append(res, p)
+	for _, project := range projects {
+		if !project.IsDeleted.Bool() {
+			res = append(res, project)
+		}
+	}
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("projects")
+	for _, project := range *c.cache {
+		if IsTempID(project.ID) {
+			res = append(res, project)
+		}
+	}
+	c.set(res)
+}
+
+// reset sets the cache to the given projects, dropping local changes.
+func (c *projectCache) reset(projects []Project) {
+	res := make([]Project, len(projects))
+	copy(res, projects)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("projects")
+	c.set(res)
+	c.pending = nil
+}
+
+// markPending keeps the project before the first local change, to roll it back.
+func (c *projectCache) markPending(id ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pending[id]; ok {
+		return
+	}
+	if c.pending == nil {
+		c.pending = map[ID]*Project{}
+	}
+	c.pending[id] = nil
+	if i, ok := c.lookup(id); ok {
+		original := (*c.cache)[i]
+		c.pending[id] = &original
+	}
+}
+
+// settle forgets the local change of the project committed successfully,
+// or rolls it back if the command failed.
+func (c *projectCache) settle(id ID, ok bool) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	original, pending := c.pending[id]
+	if !pending {
+		return
+	}
+	delete(c.pending, id)
+	if ok {
+		return
+	}
+	c.notify.record("projects")
+	i, cached := c.lookup(id)
+	switch {
+	case original == nil:
+		if cached {
+			c.removeAt(i)
+		}
+	case cached:
+		(*c.cache)[i] = *original
+	default:
+		*c.cache = append(*c.cache, *original)
+		if c.index != nil {
+			c.index[id] = len(*c.cache) - 1
 		}
 	}
-	c.cache = &res
 }
@@ -0,0 +1,235 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestProjectClient_Hierarchy(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for _, p := range []struct {
+		id, parentID ID
+		order        int
+	}{
+		{"1", "", 1},
+		{"2", "1", 2},
+		{"3", "1", 1},
+		{"4", "2", 1},
+		// cycle
+		{"5", "6", 1},
+		{"6", "5", 1},
+	} {
+		project := Project{ParentID: p.parentID, ChildOrder: p.order}
+		project.ID = p.id
+		client.Project.cache.store(project)
+	}
+
+	if children := client.Project.Children("1"); len(children) != 2 || children[0].ID != "3" {
+		t.Errorf("Expect children ordered by child order, but got %v", children)
+	}
+	if parent := client.Project.Parent("4"); parent == nil || parent.ID != "2" {
+		t.Errorf("Expect parent %s, but got %v", "2", parent)
+	}
+	if parent := client.Project.Parent("1"); parent != nil {
+		t.Errorf("Expect nil, but got %v", parent)
+	}
+	if ancestors := client.Project.Ancestors("4"); len(ancestors) != 2 || ancestors[1].ID != "1" {
+		t.Errorf("Unexpect ancestors: %v", ancestors)
+	}
+	if depth := client.Project.Depth("4"); depth != 2 {
+		t.Errorf("Expect %d, but got %d", 2, depth)
+	}
+	if depth := client.Project.Depth("5"); depth != 1 {
+		t.Errorf("Expect cycle to be stopped, but got depth %d", depth)
+	}
+}
+
+func TestProjectClient_Archive(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for _, p := range []struct {
+		id, parentID ID
+	}{{"1", ""}, {"2", "1"}, {"3", "2"}, {"4", ""}} {
+		project := Project{ParentID: p.parentID}
+		project.ID = p.id
+		client.Project.cache.store(project)
+	}
+
+	client.Project.Archive("1")
+	if active := client.Project.GetActive(); len(active) != 1 || active[0].ID != "4" {
+		t.Errorf("Expect only %s to be active, but got %v", "4", active)
+	}
+	if archived := client.Project.GetAllArchived(); len(archived) != 3 {
+		t.Errorf("Expect %d archived projects, but got %v", 3, archived)
+	}
+	client.Project.Unarchive("2")
+	if active := client.Project.GetActive(); len(active) != 2 {
+		t.Errorf("Expect %d active projects, but got %v", 2, active)
+	}
+	if p := client.Project.Resolve("3"); !p.IsArchived.Bool() {
+		t.Error("Expect descendants to be kept archived")
+	}
+	if n := len(client.Pending()); n != 2 {
+		t.Errorf("Expect %d commands, but got %d", 2, n)
+	}
+}
+
+func TestProjectClient_ReorderIDs(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for i, id := range []ID{"1", "2", "3"} {
+		project := Project{Name: "project", ChildOrder: i + 1}
+		project.ID = id
+		client.Project.cache.store(project)
+	}
+
+	if err := client.Project.ReorderIDs([]ID{"2", "3", "1"}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	b, err := json.Marshal(client.queue[0].Args)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	expect := `{"projects":[{"id":"2","child_order":1},{"id":"3","child_order":2},{"id":"1","child_order":3}]}`
+	if client.queue[0].Type != "project_reorder" || string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	for id, order := range map[ID]int{"1": 3, "2": 1, "3": 2} {
+		if project := client.Project.Resolve(id); project.ChildOrder != order {
+			t.Errorf("Expect %d, but got %d", order, project.ChildOrder)
+		}
+	}
+	if err := client.Project.Reorder(nil); err == nil {
+		t.Error("Expect error for no projects, but got nil")
+	}
+}
+
+func TestProjectClient_SetViewStyle(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	project := Project{Name: "project", ViewStyle: ViewStyleList}
+	project.ID = "1"
+	client.Project.cache.store(project)
+
+	if err := client.Project.SetViewStyle("1", "grid"); err == nil {
+		t.Error("Expect error for unknown style, but got nil")
+	}
+	if err := client.Project.SetViewStyle("1", ViewStyleBoard); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(client.queue) != 1 || client.queue[0].Type != "project_update" {
+		t.Fatalf("Unexpect queue: %v", client.queue)
+	}
+	b, err := json.Marshal(client.queue[0].Args)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := `{"id":"1","view_style":"board"}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	if cached := client.Project.Resolve("1"); cached.ViewStyle != ViewStyleBoard {
+		t.Errorf("Expect %s, but got %s", ViewStyleBoard, cached.ViewStyle)
+	}
+}
+
+func TestProjectClient_GetData(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/get_data" {
+			t.Errorf("Expect %s, but got %s", "/projects/get_data", r.URL.Path)
+		}
+		if r.FormValue("project_id") != "1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{
+			"project": {"id": 1, "name": "Work"},
+			"sections": [{"id": 10, "name": "Todo", "project_id": 1}],
+			"items": [{"id": 100, "content": "item", "project_id": 1, "section_id": 10}],
+			"project_notes": [{"id": 1000, "project_id": 1, "content": "note"}]
+		}`))
+	})
+	defer teardown()
+
+	data, err := client.Project.GetData(context.Background(), "1")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if data.Project.Name != "Work" || len(data.Sections) != 1 || len(data.Items) != 1 || len(data.Notes) != 1 {
+		t.Fatalf("Unexpect data: %v", data)
+	}
+	if client.Project.Resolve("1") == nil || client.Section.Resolve("10") == nil ||
+		client.Item.Resolve("100") == nil || client.Note.Resolve("1000") == nil {
+		t.Error("Expect the data to be cached")
+	}
+
+	if _, err = client.Project.GetData(context.Background(), "2"); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Expect %s, but got %v", ErrProjectNotFound, err)
+	}
+}
+
+func TestProjectClient_UpdateFields(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		status := map[UUID]interface{}{}
+		for _, command := range commands {
+			if commandTargetID(command) == "1" {
+				status[command.UUID] = "ok"
+			} else {
+				status[command.UUID] = map[string]interface{}{"error_code": 21, "error": "Project not found"}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token":  "next-token",
+			"sync_status": status,
+		})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	for _, id := range []ID{"1", "2"} {
+		project := Project{Name: "before", Color: Red}
+		project.ID = id
+		client.Project.cache.store(project)
+	}
+
+	if err := client.Project.UpdateFields("1", map[string]interface{}{"parent_id": "20"}); err == nil {
+		t.Error("Expect error for parent_id")
+	}
+	if err := client.Project.UpdateFields("1", map[string]interface{}{"name": "after", "collapsed": IntBool(true)}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Project.UpdateFields("2", map[string]interface{}{"name": "rejected"}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	pending := client.Pending()
+	if len(pending) != 2 {
+		t.Fatalf("Expect %d commands, but got %d", 2, len(pending))
+	}
+	b, _ := json.Marshal(pending[0].Args)
+	if expect := `{"collapsed":true,"id":"1","name":"after"}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	if project := client.Project.Resolve("1"); project.Name != "after" || !project.Collapsed.Bool() || project.Color != Red {
+		t.Errorf("Unexpect project: %v", project)
+	}
+	if project := client.Project.Resolve("2"); project.Name != "rejected" {
+		t.Errorf("Expect %s before commit, but got %s", "rejected", project.Name)
+	}
+
+	var commitErr *CommitError
+	if err := client.Commit(context.Background()); !errors.As(err, &commitErr) || len(commitErr.Errors) != 1 {
+		t.Fatalf("Expect 1 command error, but got %v", err)
+	}
+	if project := client.Project.Resolve("1"); project == nil || project.Name != "after" {
+		t.Errorf("Expect update to be kept, but got %v", project)
+	}
+	if project := client.Project.Resolve("2"); project == nil || project.Name != "before" {
+		t.Errorf("Expect update to be rolled back, but got %v", project)
+	}
+}
@@ -0,0 +1,104 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// maxQueryLimit is the maximum number of items returned in a request.
+const maxQueryLimit = 200
+
+type QueryOpts struct {
+	// Limit defaults to maxQueryLimit.
+	Limit  int
+	Offset int
+}
+
+// QueryError is returned when the server rejects the filter query.
+type QueryError struct {
+	Query      string
+	StatusCode int
+	Body       string
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("invalid query %q, status code: %d, body: %s", e.Query, e.StatusCode, e.Body)
+}
+
+// queryResult is a result of a query. The query endpoint accepts some queries at once,
+// and returns a result for each.
+type queryResult struct {
+	Query string `json:"query"`
+	Type  string `json:"type"`
+	Data  []Item `json:"data"`
+}
+
+// Query returns a page of the items matched by the filter query, e.g. "today & @work".
+// Obviously malformed queries are rejected by ValidateFilterQuery before the request.
+func (c *ItemClient) Query(ctx context.Context, query string, opts QueryOpts) ([]Item, error) {
+	if err := ValidateFilterQuery(query); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal([]string{query})
+	if err != nil {
+		return nil, err
+	}
+	limit := opts.Limit
+	if limit <= 0 || limit > maxQueryLimit {
+		limit = maxQueryLimit
+	}
+	values := url.Values{
+		"queries": {string(b)},
+		"limit":   {strconv.Itoa(limit)},
+		"offset":  {strconv.Itoa(opts.Offset)},
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, pathQuery, values)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode/100 == 4 {
+		defer res.Body.Close()
+		body, _ := ioutil.ReadAll(res.Body)
+		return nil, &QueryError{Query: query, StatusCode: res.StatusCode, Body: string(body)}
+	}
+	if (res.StatusCode / 100) != 2 {
+		return nil, newAPIError(res)
+	}
+	var out []queryResult
+	if err = c.decodeBody(res, &out); err != nil {
+		return nil, err
+	}
+	var items []Item
+	for _, result := range out {
+		items = append(items, result.Data...)
+	}
+	return items, nil
+}
+
+// QueryAll returns the items of all pages from opts.Offset.
+func (c *ItemClient) QueryAll(ctx context.Context, query string, opts QueryOpts) ([]Item, error) {
+	if opts.Limit <= 0 || opts.Limit > maxQueryLimit {
+		opts.Limit = maxQueryLimit
+	}
+	var res []Item
+	for {
+		items, err := c.Query(ctx, query, opts)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, items...)
+		if len(items) < opts.Limit {
+			return res, nil
+		}
+		opts.Offset += len(items)
+	}
+}
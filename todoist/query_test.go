@@ -0,0 +1,56 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+func TestItemClient_QueryAll(t *testing.T) {
+	requests := 0
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var queries []string
+		if err := json.Unmarshal([]byte(r.FormValue("queries")), &queries); err != nil || len(queries) != 1 {
+			t.Errorf("Unexpect queries: %s", r.FormValue("queries"))
+		}
+		if queries[0] == "#Unknown" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "Invalid query"}`))
+			return
+		}
+		offset, _ := strconv.Atoi(r.FormValue("offset"))
+		var items []map[string]interface{}
+		for i := offset; i < 5 && i < offset+2; i++ {
+			items = append(items, map[string]interface{}{"id": i + 1, "content": "item"})
+		}
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"query": queries[0], "type": "filter", "data": items},
+		})
+	})
+	defer teardown()
+
+	items, err := client.Item.QueryAll(context.Background(), "today & @work", QueryOpts{Limit: 2})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(items) != 5 || items[4].ID != "5" {
+		t.Errorf("Unexpect items: %v", items)
+	}
+	if requests != 3 {
+		t.Errorf("Expect %d requests, but got %d", 3, requests)
+	}
+
+	_, err = client.Item.Query(context.Background(), "#Unknown", QueryOpts{})
+	var queryErr *QueryError
+	if !errors.As(err, &queryErr) || queryErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expect *QueryError, but got %v", err)
+	}
+	requests = 0
+	if _, err = client.Item.Query(context.Background(), "(today", QueryOpts{}); err == nil || requests != 0 {
+		t.Errorf("Expect malformed query to be rejected locally, but got %v", err)
+	}
+}
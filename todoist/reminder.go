@@ -1,16 +1,337 @@
 package todoist
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+const (
+	ReminderTypeRelative = "relative"
+	ReminderTypeAbsolute = "absolute"
+	ReminderTypeLocation = "location"
+
+	LocationTriggerOnEnter = "on_enter"
+	LocationTriggerOnLeave = "on_leave"
+)
+
 type Reminder struct {
 	Entity
-	NotifyUID  ID     `json:"notify_uid"`
+	NotifyUID  ID     `json:"notify_uid,omitempty"`
 	ItemID     ID     `json:"item_id"`
-	Service    string `json:"service"`
+	Service    string `json:"service,omitempty"`
 	Type       string `json:"type"`
-	Due        Due    `json:"due"`
-	MmOffset   int    `json:"mm_offset"`
-	Name       string `json:"name"`
-	LocLat     string `json:"loc_lat"`
-	LocLong    string `json:"loc_long"`
-	LocTrigger string `json:"loc_trigger"`
-	Radius     int    `json:"radius"`
+	Due        *Due   `json:"due,omitempty"`
+	MmOffset   int    `json:"minute_offset,omitempty"`
+	Name       string `json:"name,omitempty"`
+	LocLat     string `json:"loc_lat,omitempty"`
+	LocLong    string `json:"loc_long,omitempty"`
+	LocTrigger string `json:"loc_trigger,omitempty"`
+	Radius     int    `json:"radius,omitempty"`
+}
+
+// MarshalJSON always includes the minute offset of a relative reminder, even 0 at the due time.
+func (r Reminder) MarshalJSON() ([]byte, error) {
+	type reminder Reminder
+	if r.Type != ReminderTypeRelative {
+		return json.Marshal(reminder(r))
+	}
+	return json.Marshal(struct {
+		reminder
+		MinuteOffset int `json:"minute_offset"`
+	}{reminder(r), r.MmOffset})
+}
+
+type NewReminderOpts struct {
+	NotifyUID ID
+	Service   string
+}
+
+func newReminder(itemID ID, reminderType string, opts *NewReminderOpts) (*Reminder, error) {
+	if itemID.IsZero() {
+		return nil, errors.New("new reminder requires an item id")
+	}
+	if opts == nil {
+		opts = &NewReminderOpts{}
+	}
+	reminder := Reminder{
+		ItemID:    itemID,
+		Type:      reminderType,
+		NotifyUID: opts.NotifyUID,
+		Service:   opts.Service,
+	}
+	reminder.ID = GenerateTempID()
+	return &reminder, nil
+}
+
+// NewRelativeReminder returns a reminder notified minuteOffset minutes before the due of the item.
+func NewRelativeReminder(itemID ID, minuteOffset int, opts *NewReminderOpts) (*Reminder, error) {
+	if minuteOffset < 0 {
+		return nil, errors.New("new relative reminder requires a non-negative minute offset")
+	}
+	reminder, err := newReminder(itemID, ReminderTypeRelative, opts)
+	if err != nil {
+		return nil, err
+	}
+	reminder.MmOffset = minuteOffset
+	return reminder, nil
+}
+
+// NewAbsoluteReminder returns a reminder notified at the given due.
+func NewAbsoluteReminder(itemID ID, due Due, opts *NewReminderOpts) (*Reminder, error) {
+	if due.Date.IsZero() {
+		return nil, errors.New("new absolute reminder requires a due date")
+	}
+	reminder, err := newReminder(itemID, ReminderTypeAbsolute, opts)
+	if err != nil {
+		return nil, err
+	}
+	reminder.Due = &due
+	return reminder, nil
+}
+
+// NewLocationReminder returns a reminder notified when entering or leaving the location.
+func NewLocationReminder(itemID ID, name, lat, long string, radius int, trigger string, opts *NewReminderOpts) (*Reminder, error) {
+	if len(lat) == 0 || len(long) == 0 {
+		return nil, errors.New("new location reminder requires a latitude and a longitude")
+	}
+	if trigger != LocationTriggerOnEnter && trigger != LocationTriggerOnLeave {
+		return nil, errors.New("new location reminder requires a trigger on_enter or on_leave")
+	}
+	reminder, err := newReminder(itemID, ReminderTypeLocation, opts)
+	if err != nil {
+		return nil, err
+	}
+	reminder.Name = name
+	reminder.LocLat = lat
+	reminder.LocLong = long
+	reminder.Radius = radius
+	reminder.LocTrigger = trigger
+	return reminder, nil
+}
+
+// ReminderClient encapsulate client operations for reminders.
+type ReminderClient struct {
+	*Client
+	cache *reminderCache
+}
+
+// AutoReminderMinutes returns the minutes before the due of the reminder the official apps add
+// to items with a due time, by the user's settings. It returns -1 before the user is synced.
+func (c *ReminderClient) AutoReminderMinutes() int {
+	user := c.user.get()
+	if user == nil {
+		return -1
+	}
+	return user.AutoReminder
+}
+
+// Add adds the reminder. The item may be added in the same commit by its temporary id,
+// which the server resolves, or which is replaced by the id if it is committed already.
+// It returns ErrInvalidTempID for a temporary id of an item not added.
+func (c *ReminderClient) Add(reminder Reminder) (*Reminder, error) {
+	if IsTempID(reminder.ItemID) {
+		if resolved := c.ResolveTempID(reminder.ItemID); resolved != reminder.ItemID {
+			reminder.ItemID = resolved
+		} else if !c.queuedTempID(reminder.ItemID) {
+			return nil, fmt.Errorf("%w: item %s is not added", ErrInvalidTempID, reminder.ItemID)
+		}
+	}
+	if c.optimistic() {
+		c.cache.store(reminder)
+	}
+	c.enqueue("reminder_add", reminder, reminder.ID)
+	return &reminder, nil
+}
+
+func (c *ReminderClient) Update(reminder Reminder) (*Reminder, error) {
+	c.enqueue("reminder_update", reminder, "")
+	return &reminder, nil
+}
+
+func (c *ReminderClient) Delete(id ID) error {
+	c.enqueue("reminder_delete", map[string]ID{"id": id}, "")
+	return nil
+}
+
+// DeleteIfExists deletes the reminder only if it is cached. See SectionClient.DeleteIfExists.
+func (c *ReminderClient) DeleteIfExists(id ID, opts *DeleteOpts) error {
+	if c.Resolve(id) == nil {
+		return opts.notFound("reminder", id)
+	}
+	return c.Delete(id)
+}
+
+func (c *ReminderClient) GetAll() []Reminder {
+	return c.cache.getAll()
+}
+
+// Resolve returns a copy of the cached reminder. Use Update to modify it.
+func (c *ReminderClient) Resolve(id ID) *Reminder {
+	return c.cache.resolve(id)
+}
+
+// GetAllForItem returns all the cached reminders that belong to the given item.
+func (c *ReminderClient) GetAllForItem(itemID ID) []Reminder {
+	var res []Reminder
+	for _, r := range c.cache.getAll() {
+		if r.ItemID == itemID {
+			res = append(res, r)
+		}
+	}
+	return res
+}
+
+type reminderCache struct {
+	cache *[]Reminder
+	// index maps ids to positions in cache.
+	index  map[ID]int
+	mu     sync.RWMutex
+	notify *notifier
+}
+
+func (c *reminderCache) getAll() []Reminder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Reminder, len(*c.cache))
+	copy(res, *c.cache)
+	return res
+}
+
+// resolve returns a copy of the cached reminder, so that it is safe to read
+// while the cache is updated. Modifying it does not affect the cache.
+func (c *reminderCache) resolve(id ID) *Reminder {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	i, ok := c.lookup(id)
+	if !ok {
+		return nil
+	}
+	res := (*c.cache)[i]
+	return &res
+}
+
+// lookup returns the position of the reminder in the cache. The caller must hold the lock.
+// The cache is scanned if it is not indexed yet.
+func (c *reminderCache) lookup(id ID) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[id]
+		return i, ok
+	}
+	for i, reminder := range *c.cache {
+		if reminder.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// set swaps the cache for the reminders and indexes them. The caller must hold the lock.
+func (c *reminderCache) set(reminders []Reminder) {
+	c.cache = &reminders
+	c.index = indexReminders(reminders)
+}
+
+func indexReminders(reminders []Reminder) map[ID]int {
+	res := make(map[ID]int, len(reminders))
+	for i, reminder := range reminders {
+		res[reminder.ID] = i
+	}
+	return res
+}
+
+func (c *reminderCache) store(reminder Reminder) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("reminders")
+	i, ok := c.lookup(reminder.ID)
+	switch {
+	case reminder.IsDeleted.Bool():
+		if ok {
+			c.removeAt(i)
+		}
+	case ok:
+		(*c.cache)[i] = reminder
+	default:
+		*c.cache = append(*c.cache, reminder)
+		if c.index != nil {
+			c.index[reminder.ID] = len(*c.cache) - 1
+		}
+	}
+}
+
+func (c *reminderCache) remove(reminder Reminder) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("reminders")
+	if i, ok := c.lookup(reminder.ID); ok {
+		c.removeAt(i)
+	}
+}
+
+// removeAt removes the reminder at the position, and shifts the positions of the following ones in the index.
+// The caller must hold the lock.
+func (c *reminderCache) removeAt(i int) {
+	reminders := *c.cache
+	id := reminders[i].ID
+	copy(reminders[i:], reminders[i+1:])
+	reminders = reminders[:len(reminders)-1]
+	*c.cache = reminders
+	if c.index != nil {
+		delete(c.index, id)
+		for j := i; j < len(reminders); j++ {
+			c.index[reminders[j].ID] = j
+		}
+	}
+}
+
+func (c *reminderCache) replaceTempIDs(mapping map[ID]ID) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("reminders")
+	res := make([]Reminder, len(*c.cache))
+	copy(res, *c.cache)
+	for i, reminder := range res {
+		if id, ok := mapping[reminder.ID]; ok {
+			res[i].ID = id
+		}
+		res[i].ItemID = mapTempID(reminder.ItemID, mapping)
+	}
+	c.set(res)
+}
+
+// replace swaps the cache for the given reminders at once.
+// Cached reminders with temporary ids are kept, since they are not committed yet.
+func (c *reminderCache) replace(reminders []Reminder) {
+	var res []Reminder
+	for _, reminder := range reminders {
+		if !reminder.IsDeleted.Bool() {
+			res = append(res, reminder)
+		}
+	}
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("reminders")
+	for _, reminder := range *c.cache {
+		if IsTempID(reminder.ID) {
+			res = append(res, reminder)
+		}
+	}
+	c.set(res)
+}
+
+// reset sets the cache to the given reminders, dropping local changes.
+func (c *reminderCache) reset(reminders []Reminder) {
+	res := make([]Reminder, len(reminders))
+	copy(res, reminders)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("reminders")
+	c.set(res)
 }
@@ -0,0 +1,179 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewReminder(t *testing.T) {
+	reminder, err := NewRelativeReminder("1", 30, &NewReminderOpts{})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	b, _ := json.Marshal(reminder)
+	expect := `{"id":"` + reminder.ID.String() + `","item_id":"1","type":"relative","minute_offset":30}`
+	if string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	// a relative reminder at the due time.
+	if reminder, err = NewRelativeReminder("1", 0, nil); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	b, _ = json.Marshal(reminder)
+	expect = `{"id":"` + reminder.ID.String() + `","item_id":"1","type":"relative","minute_offset":0}`
+	if string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	if _, err = NewRelativeReminder("1", -1, nil); err == nil {
+		t.Error("Expect error, but no error")
+	}
+	absolute, err := NewAbsoluteReminder("1", NewFullDayDue(time.Date(2020, 1, 2, 0, 0, 0, 0, time.Local)), nil)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if b, _ = json.Marshal(absolute); strings.Contains(string(b), "minute_offset") {
+		t.Errorf("Expect no minute_offset, but got %s", string(b))
+	}
+	var decoded Reminder
+	if err = json.Unmarshal([]byte(`{"id": 1, "item_id": 2, "type": "relative", "minute_offset": 15}`), &decoded); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if decoded.MmOffset != 15 {
+		t.Errorf("Expect %d, but got %d", 15, decoded.MmOffset)
+	}
+
+	reminder, err = NewLocationReminder("1", "home", "35.68", "139.76", 100, LocationTriggerOnLeave, &NewReminderOpts{})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if reminder.Type != ReminderTypeLocation || reminder.LocTrigger != LocationTriggerOnLeave {
+		t.Errorf("Unexpect reminder: %v", reminder)
+	}
+
+	if _, err = NewLocationReminder("1", "home", "35.68", "139.76", 100, "invalid", &NewReminderOpts{}); err == nil {
+		t.Error("Expect error, but no error")
+	}
+	if _, err = NewAbsoluteReminder("1", Due{}, &NewReminderOpts{}); err == nil {
+		t.Error("Expect error, but no error")
+	}
+	if _, err = NewRelativeReminder("", 30, &NewReminderOpts{}); err == nil {
+		t.Error("Expect error, but no error")
+	}
+}
+
+func TestItemClient_AddWithAutoReminder(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token", "user": {"id": 1, "auto_reminder": 30}}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	item, _ := NewItem("meeting", &NewItemOpts{Due: NewFloatingDue(time.Date(2020, 1, 2, 15, 0, 0, 0, time.Local))})
+	if _, err := client.Item.AddWithOpts(*item, &ItemAddOpts{WithAutoReminder: true}); err == nil {
+		t.Error("Expect error before the user is synced")
+	}
+	if minutes := client.Reminder.AutoReminderMinutes(); minutes != -1 {
+		t.Errorf("Expect %d, but got %d", -1, minutes)
+	}
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if minutes := client.Reminder.AutoReminderMinutes(); minutes != 30 {
+		t.Errorf("Expect %d, but got %d", 30, minutes)
+	}
+
+	if _, err := client.Item.AddWithOpts(*item, &ItemAddOpts{WithAutoReminder: true}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	allDay, _ := NewItem("holiday", &NewItemOpts{Due: NewFullDayDue(time.Now())})
+	if _, err := client.Item.AddWithOpts(*allDay, &ItemAddOpts{WithAutoReminder: true}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	plain, _ := NewItem("plain", &NewItemOpts{Due: item.Due})
+	if _, err := client.Item.AddWithOpts(*plain, nil); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	// an item built without NewItem is given a temporary id for the reminder.
+	literal, err := client.Item.AddWithOpts(Item{Content: "literal", Due: item.Due}, &ItemAddOpts{WithAutoReminder: true})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if !IsTempID(literal.ID) {
+		t.Errorf("Expect a temporary id, but got %s", literal.ID)
+	}
+	var types []string
+	for _, command := range client.Pending() {
+		types = append(types, command.Type)
+	}
+	if expect := []string{"item_add", "reminder_add", "item_add", "item_add", "item_add", "reminder_add"}; !reflect.DeepEqual(types, expect) {
+		t.Errorf("Expect %v, but got %v", expect, types)
+	}
+	if pending := client.Pending(); pending[4].TempID != literal.ID {
+		t.Errorf("Expect %s, but got %s", literal.ID, pending[4].TempID)
+	}
+	reminders := client.Reminder.GetAll()
+	if len(reminders) != 2 || reminders[0].ItemID != item.ID || reminders[0].MmOffset != 30 || reminders[1].ItemID != literal.ID {
+		t.Errorf("Unexpect reminders: %v", reminders)
+	}
+}
+
+func TestItemClient_AddWithReminder(t *testing.T) {
+	var commands []Command
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token":      "next-token",
+			"temp_id_mapping": map[ID]int{commands[0].TempID: 100, commands[1].TempID: 200},
+		})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+
+	item, _ := NewItem("item", &NewItemOpts{Due: NewFloatingDue(time.Date(2020, 1, 2, 9, 0, 0, 0, time.UTC))})
+	itemID, reminderID, err := client.Item.AddWithReminder(*item, 15)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if itemID != item.ID || !IsTempID(reminderID) {
+		t.Errorf("Unexpect ids: %s, %s", itemID, reminderID)
+	}
+	if err = client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(commands) != 2 || commands[0].Type != "item_add" || commands[1].Type != "reminder_add" {
+		t.Fatalf("Unexpect commands: %v", commands)
+	}
+	args := commands[1].Args.(map[string]interface{})
+	if args["item_id"] != itemID.String() || args["minute_offset"] != float64(15) {
+		t.Errorf("Expect the reminder of item %s, but got %v", itemID, args)
+	}
+	if reminder := client.Reminder.Resolve("200"); reminder == nil || reminder.ItemID != "100" {
+		t.Errorf("Expect the reminder of item %s, but got %v", "100", reminder)
+	}
+
+	// the committed temporary id is replaced by the id.
+	reminder, _ := NewRelativeReminder(itemID, 30, nil)
+	if _, err = client.Reminder.Add(*reminder); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if pending := client.Pending(); len(pending) != 1 || pending[0].Args.(Reminder).ItemID != "100" {
+		t.Errorf("Expect the reminder of item %s, but got %v", "100", pending)
+	}
+
+	reminder, _ = NewRelativeReminder(GenerateTempID(), 30, nil)
+	if _, err = client.Reminder.Add(*reminder); !errors.Is(err, ErrInvalidTempID) {
+		t.Errorf("Expect %s, but got %v", ErrInvalidTempID, err)
+	}
+	item, _ = NewItem("without due", nil)
+	if _, _, err = client.Item.AddWithReminder(*item, 15); err == nil {
+		t.Error("Expect error, but got nil")
+	}
+}
@@ -0,0 +1,160 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests are retried on network errors and 5xx responses.
+// Only GET requests and sync requests are retried, since the server ignores
+// commands whose uuid is already processed.
+// Rate limited requests are retried after the wait suggested by the server,
+// unless NoWaitOnRateLimit is set.
+type RetryPolicy struct {
+	MaxAttempts       int
+	BaseDelay         time.Duration
+	NoWaitOnRateLimit bool
+}
+
+// DefaultRetryPolicy is used by a new client unless WithRetryPolicy is given.
+var DefaultRetryPolicy = &RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+}
+
+// delay returns a jittered exponential backoff before the given attempt (starts from 1).
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay << uint(attempt-1)
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func isRetryable(req *http.Request) bool {
+	return req.Method == http.MethodGet || path.Base(req.URL.Path) == "sync"
+}
+
+// isRewindable reports whether the request body can be sent again.
+func isRewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// RateLimitError is returned when the server responds 429 and the request is not retried.
+// RetryAfter is the wait suggested by the Retry-After header.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s", e.RetryAfter)
+}
+
+// parseRetryAfter parses Retry-After header in delta-seconds or HTTP-date form.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if sec, err := strconv.Atoi(value); err == nil {
+		if sec < 0 {
+			return 0
+		}
+		return time.Duration(sec) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// do sends the request with the client's retry policy, within the timeout by WithRequestTimeout
+// if the context of the request has no deadline. The timeout covers the retries and reading the body.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if c.requestTimeout <= 0 {
+		return c.doRetry(req)
+	}
+	if _, ok := req.Context().Deadline(); ok {
+		return c.doRetry(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), c.requestTimeout)
+	res, err := c.doRetry(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	res.Body = &cancelOnClose{res.Body, cancel}
+	return res, nil
+}
+
+// cancelOnClose cancels the context of the request when the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// doRetry sends the request with the client's retry policy.
+// It returns ctx.Err() when the context of the request is done, and *TransportError when no response is received.
+// A rate limited request is retried after Retry-After regardless of its method,
+// since the server has not processed it.
+func (c *Client) doRetry(req *http.Request) (*http.Response, error) {
+	attempts := 1
+	if c.RetryPolicy != nil && c.RetryPolicy.MaxAttempts > 1 && isRetryable(req) {
+		attempts = c.RetryPolicy.MaxAttempts
+	}
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		res, err := c.HTTPClient.Do(req)
+		c.observe(req, res, err, start, attempt)
+		if err != nil && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var wait time.Duration
+		switch {
+		case err == nil && res.StatusCode == http.StatusTooManyRequests:
+			res.Body.Close()
+			rateLimitErr := &RateLimitError{RetryAfter: parseRetryAfter(res.Header.Get("Retry-After"), time.Now())}
+			p := c.RetryPolicy
+			if p == nil || p.NoWaitOnRateLimit || attempt >= p.MaxAttempts || ctx.Err() != nil || !isRewindable(req) {
+				return nil, rateLimitErr
+			}
+			wait = rateLimitErr.RetryAfter
+			if wait == 0 {
+				wait = p.delay(attempt)
+			}
+		case err == nil && res.StatusCode/100 != 5, attempt >= attempts, ctx.Err() != nil:
+			if err != nil {
+				return nil, &TransportError{Method: req.Method, URL: redactURL(req.URL), Err: redactError(err)}
+			}
+			return res, nil
+		default:
+			if err == nil {
+				res.Body.Close()
+			}
+			wait = c.RetryPolicy.delay(attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		c.Logger.Printf("retry %s %s (attempt %d)", req.Method, req.URL.Path, attempt+1)
+	}
+}
@@ -0,0 +1,96 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_Retry(t *testing.T) {
+	requests := 0
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"sync_token": "token"}`))
+	})
+	defer teardown()
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if requests != 3 {
+		t.Errorf("Expect %d requests, but got %d", 3, requests)
+	}
+
+	requests = 0
+	client.Completed.GetStats(context.Background())
+	if requests != 1 {
+		t.Errorf("Expect non-idempotent request not to be retried, but got %d requests", requests)
+	}
+}
+
+func TestClient_RetryCanceled(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	defer teardown()
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := client.Sync(ctx, []Command{}); err != context.DeadlineExceeded {
+		t.Errorf("Expect %s, but got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		s string
+		v time.Duration
+	}{
+		{"120", 2 * time.Minute},
+		{"Wed, 01 Jan 2020 00:00:30 GMT", 30 * time.Second},
+		{"Tue, 31 Dec 2019 23:59:00 GMT", 0},
+		{"invalid", 0},
+	}
+	for _, test := range tests {
+		if v := parseRetryAfter(test.s, now); v != test.v {
+			t.Errorf("Expect %s, but got %s", test.v, v)
+		}
+	}
+}
+
+func TestClient_RateLimit(t *testing.T) {
+	requests := 0
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{}`))
+	})
+	defer teardown()
+	client.RetryPolicy = &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}
+
+	if _, err := client.Completed.GetStats(context.Background()); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if requests != 2 {
+		t.Errorf("Expect %d requests, but got %d", 2, requests)
+	}
+
+	requests = 0
+	client.RetryPolicy.NoWaitOnRateLimit = true
+	_, err := client.Completed.GetStats(context.Background())
+	if _, ok := err.(*RateLimitError); !ok {
+		t.Errorf("Expect *RateLimitError, but got %v", err)
+	}
+}
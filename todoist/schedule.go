@@ -0,0 +1,74 @@
+package todoist
+
+import (
+	"time"
+)
+
+// ItemScheduleOpts configures ScheduleWithOpts.
+type ItemScheduleOpts struct {
+	// ClearRecurrence makes a recurring item a one-off on the new date.
+	// By default, the recurrence is kept and the date becomes its next occurrence.
+	ClearRecurrence bool
+}
+
+// Schedule sets the due of the item to when, keeping the recurrence of a cached recurring item.
+// An all-day due is on the date of when, without time nor timezone.
+// Otherwise the due is fixed at when in the user's timezone, or in UTC before the user is synced.
+func (c *ItemClient) Schedule(id ID, when time.Time, allDay bool) error {
+	return c.ScheduleWithOpts(id, when, allDay, &ItemScheduleOpts{})
+}
+
+// ScheduleWithOpts sets the due of the item as Schedule does, along with opts.
+func (c *ItemClient) ScheduleWithOpts(id ID, when time.Time, allDay bool, opts *ItemScheduleOpts) error {
+	var due Due
+	if allDay {
+		due = NewFullDayDue(when)
+	} else {
+		timezone := "UTC"
+		if user := c.user.get(); user != nil {
+			if _, err := time.LoadLocation(user.TzInfo.Timezone); err == nil && len(user.TzInfo.Timezone) != 0 {
+				timezone = user.TzInfo.Timezone
+			}
+		}
+		var err error
+		if due, err = NewZonedDue(when, timezone); err != nil {
+			return err
+		}
+	}
+	item := c.Resolve(id)
+	if item != nil && item.Due.IsRecurring && !opts.ClearRecurrence {
+		// the server parses the string again, so that it would drop the recurrence without it.
+		due.String = item.Due.String
+		due.Lang = item.Due.Lang
+		due.IsRecurring = true
+	}
+	c.enqueue("item_update", map[string]interface{}{
+		"id":  id,
+		"due": due,
+	}, "")
+	if item != nil && c.optimistic() {
+		item.Due = due
+		c.cache.store(*item)
+	}
+	return nil
+}
+
+// ScheduleToday makes the item due today in the user's timezone, as an all-day due.
+func (c *ItemClient) ScheduleToday(id ID) error {
+	return c.Schedule(id, c.userNow(time.Now()), true)
+}
+
+// ScheduleTomorrow makes the item due tomorrow in the user's timezone, as an all-day due.
+func (c *ItemClient) ScheduleTomorrow(id ID) error {
+	return c.Schedule(id, c.userNow(time.Now()).AddDate(0, 0, 1), true)
+}
+
+// userNow returns now in the user's timezone, or as is before the user is synced.
+func (c *Client) userNow(now time.Time) time.Time {
+	if user := c.user.get(); user != nil {
+		if loc, err := user.Location(); err == nil {
+			return now.In(loc)
+		}
+	}
+	return now
+}
@@ -0,0 +1,77 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestItemClient_Schedule(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	client.user.store(User{TzInfo: TzInfo{Timezone: "Asia/Tokyo", Hours: 9}})
+	client.Item.cache.store(Item{Entity: Entity{ID: "1"}, Content: "one-off"})
+	recurring := Item{Entity: Entity{ID: "2"}, Content: "recurring"}
+	recurring.Due = Due{Date: Time{time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)}, String: "every day", Lang: "en", IsRecurring: true}
+	client.Item.cache.store(recurring)
+
+	when := time.Date(2020, 1, 2, 1, 30, 0, 0, time.UTC)
+	if err := client.Item.Schedule("1", when, false); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.Schedule("1", when, true); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.Schedule("2", when, true); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Item.ScheduleWithOpts("2", when, true, &ItemScheduleOpts{ClearRecurrence: true}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+
+	expects := []string{
+		`{"date":"2020-01-02T01:30:00Z","timezone":"Asia/Tokyo","string":"","is_recurring":false}`,
+		`{"date":"2020-01-02","timezone":null,"string":"","is_recurring":false}`,
+		`{"date":"2020-01-02","timezone":null,"string":"every day","lang":"en","is_recurring":true}`,
+		`{"date":"2020-01-02","timezone":null,"string":"","is_recurring":false}`,
+	}
+	if len(client.queue) != len(expects) {
+		t.Fatalf("Expect %d, but got %d", len(expects), len(client.queue))
+	}
+	for i, expect := range expects {
+		b, err := json.Marshal(client.queue[i].Args.(map[string]interface{})["due"])
+		if err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		if string(b) != expect {
+			t.Errorf("Expect %s, but got %s", expect, b)
+		}
+	}
+	if item := client.Item.Resolve("2"); item.Due.IsRecurring {
+		t.Errorf("Expect not recurring, but got %v", item.Due)
+	}
+}
+
+func TestItemClient_ScheduleToday(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	client.user.store(User{TzInfo: TzInfo{Timezone: "Pacific/Kiritimati", Hours: 14}})
+	loc, err := time.LoadLocation("Pacific/Kiritimati")
+	if err != nil {
+		t.Skipf("timezone is unavailable: %s", err)
+	}
+
+	if err = client.Item.ScheduleToday("1"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err = client.Item.ScheduleTomorrow("1"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	now := time.Now().In(loc)
+	for i, expect := range []string{now.Format(dateLayout), now.AddDate(0, 0, 1).Format(dateLayout)} {
+		due := client.queue[i].Args.(map[string]interface{})["due"].(Due)
+		if !due.IsFullDay() || due.Date.Format(dateLayout) != expect {
+			t.Errorf("Expect %s, but got %v", expect, due)
+		}
+	}
+}
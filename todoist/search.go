@@ -0,0 +1,30 @@
+package todoist
+
+import "strings"
+
+// SearchResults is the cached resources matched by Search.
+type SearchResults struct {
+	Projects []Project
+	Sections []Section
+	Labels   []Label
+}
+
+// Search returns the cached projects, sections and labels whose name contains substr, as FindByName does.
+// A leading "#" limits it to projects and sections, "@" to labels.
+// It does not send requests, and copies only the matched resources, e.g. to complete user input.
+func (c *Client) Search(substr string) SearchResults {
+	var res SearchResults
+	switch {
+	case strings.HasPrefix(substr, "#"):
+		substr = substr[1:]
+		res.Projects = c.Project.cache.findByName(substr)
+		res.Sections = c.Section.cache.findByName(substr)
+	case strings.HasPrefix(substr, "@"):
+		res.Labels = c.Label.cache.findByName(substr[1:])
+	default:
+		res.Projects = c.Project.cache.findByName(substr)
+		res.Sections = c.Section.cache.findByName(substr)
+		res.Labels = c.Label.cache.findByName(substr)
+	}
+	return res
+}
@@ -0,0 +1,34 @@
+package todoist
+
+import "testing"
+
+func TestClient_Search(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	project := Project{Name: "work"}
+	project.ID = "1"
+	client.Project.cache.store(project)
+	section := Section{Name: "homework", ProjectID: "1"}
+	section.ID = "2"
+	client.Section.cache.store(section)
+	label := Label{Name: "work"}
+	label.ID = "3"
+	client.Label.cache.store(label)
+
+	tests := []struct {
+		s                          string
+		projects, sections, labels int
+	}{
+		{"work", 1, 1, 1},
+		{"#work", 1, 1, 0},
+		{"@work", 0, 0, 1},
+		{"home", 0, 1, 0},
+		{"none", 0, 0, 0},
+	}
+	for _, test := range tests {
+		res := client.Search(test.s)
+		if len(res.Projects) != test.projects || len(res.Sections) != test.sections || len(res.Labels) != test.labels {
+			t.Errorf("Unexpect results for %s: %v", test.s, res)
+		}
+	}
+}
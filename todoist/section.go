@@ -0,0 +1,838 @@
+package todoist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type Section struct {
+	Entity
+	Name         string  `json:"name"`
+	ProjectID    ID      `json:"project_id"`
+	SectionOrder int     `json:"section_order"`
+	Collapsed    IntBool `json:"collapsed"`
+	SyncID       int     `json:"sync_id,omitempty"`
+	IsArchived   IntBool `json:"is_archived"`
+	DateArchived Time    `json:"archived_at"`
+	DateAdded    Time    `json:"added_at"`
+}
+
+type NewSectionOpts struct {
+	ProjectID    ID
+	SectionOrder int
+}
+
+func NewSection(name string, opts *NewSectionOpts) (*Section, error) {
+	if len(name) == 0 {
+		return nil, errors.New("new section requires a name")
+	}
+	if opts == nil {
+		opts = &NewSectionOpts{}
+	}
+	section := Section{
+		Name:         name,
+		ProjectID:    opts.ProjectID,
+		SectionOrder: opts.SectionOrder,
+	}
+	section.ID = GenerateTempID()
+	return &section, nil
+}
+
+func (s Section) String() string {
+	return s.Name
+}
+
+func (s Section) ColorString() string {
+	return s.String()
+}
+
+type SectionClient struct {
+	*Client
+	cache *sectionCache
+}
+
+// Add adds the section. The section is cached until it is committed or discarded.
+func (c *SectionClient) Add(section Section) (*Section, error) {
+	if c.optimistic() {
+		c.cache.markPending(section.ID)
+		c.cache.store(section)
+	}
+	c.enqueue("section_add", section, section.ID)
+	return &section, nil
+}
+
+// Update updates the section. The cached section is updated as well,
+// and it is rolled back if the command fails on Commit.
+func (c *SectionClient) Update(section Section) (*Section, error) {
+	if err := validateName("section", section.Name); err != nil {
+		return nil, err
+	}
+	if c.optimistic() {
+		c.cache.markPending(section.ID)
+		c.cache.store(section)
+	}
+	c.enqueue("section_update", section, "")
+	return &section, nil
+}
+
+// sectionUpdateFields are the fields UpdateFields accepts. Use Move to change project_id.
+var sectionUpdateFields = []string{"name", "collapsed"}
+
+// UpdateFields updates only the given fields of the section, e.g. {"name": "Done"},
+// so that the other fields are not cleared by zero values. Accepted fields are
+// "name" and "collapsed" (IntBool).
+func (c *SectionClient) UpdateFields(id ID, fields map[string]interface{}) error {
+	args, err := updateArgs(id, fields, sectionUpdateFields)
+	if err != nil {
+		return err
+	}
+	if err = validateNameField("section", fields); err != nil {
+		return err
+	}
+	if section := c.cache.resolve(id); section != nil && c.optimistic() {
+		if err = mergeFields(section, fields); err != nil {
+			return err
+		}
+		c.cache.markPending(id)
+		c.cache.store(*section)
+	}
+	c.enqueue("section_update", args, "")
+	return nil
+}
+
+// SetCollapsed collapses or expands the items of the section, as the official apps show them.
+// The cached section is rolled back if the command fails on Commit, as UpdateFields does.
+func (c *SectionClient) SetCollapsed(id ID, collapsed bool) error {
+	return c.UpdateFields(id, map[string]interface{}{"collapsed": IntBool(collapsed)})
+}
+
+// SectionUpdateOpts configures UpdateWithOpts.
+type SectionUpdateOpts struct {
+	// Base is the section as read before the modification, e.g. a copy of Resolve.
+	Base *Section
+	// ChangedOnly sends only the fields which differ from Base, so that fields
+	// changed by others in the meantime are not overwritten.
+	ChangedOnly bool
+	// Strict rejects the update with *ConflictError if the section changed since
+	// Base was read. Only changes known by the client are detected, so sync before
+	// the update to detect recent ones.
+	Strict bool
+}
+
+// UpdateWithOpts updates the section as Update does, with the conflict handling of opts.
+// nil opts is the same as the zero value.
+func (c *SectionClient) UpdateWithOpts(section Section, opts *SectionUpdateOpts) (*Section, error) {
+	if err := validateName("section", section.Name); err != nil {
+		return nil, err
+	}
+	if opts == nil {
+		opts = &SectionUpdateOpts{}
+	}
+	if opts.Base == nil {
+		if opts.ChangedOnly || opts.Strict {
+			return nil, errors.New("require base section to detect changes")
+		}
+		return c.Update(section)
+	}
+	if opts.Strict {
+		synced := c.cache.synced(section.ID)
+		if synced == nil || !equalFields(sectionFields(*synced), sectionFields(*opts.Base)) {
+			return nil, &ConflictError{Resource: "section", ID: section.ID}
+		}
+	}
+	if !opts.ChangedOnly {
+		return c.Update(section)
+	}
+	args := map[string]json.RawMessage{}
+	base := sectionFields(*opts.Base)
+	for k, v := range sectionFields(section) {
+		if !bytes.Equal(v, base[k]) {
+			args[k] = v
+		}
+	}
+	args["id"], _ = section.ID.MarshalJSON()
+	if c.optimistic() {
+		c.cache.markPending(section.ID)
+		c.cache.store(section)
+	}
+	c.enqueue("section_update", args, "")
+	return &section, nil
+}
+
+// sectionFields returns the encoded fields of the section by name.
+func sectionFields(section Section) map[string]json.RawMessage {
+	b, _ := json.Marshal(section)
+	var res map[string]json.RawMessage
+	json.Unmarshal(b, &res)
+	return res
+}
+
+func equalFields(a, b map[string]json.RawMessage) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if !bytes.Equal(v, b[k]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *SectionClient) Move(id, projectID ID) error {
+	c.enqueue("section_move", map[string]ID{
+		"id":         id,
+		"project_id": projectID,
+	}, "")
+	c.apply(id, func(section *Section) {
+		section.ProjectID = projectID
+	})
+	return nil
+}
+
+func (c *SectionClient) Delete(id ID) error {
+	c.enqueue("section_delete", map[string]ID{"id": id}, "")
+	c.apply(id, func(section *Section) {
+		section.IsDeleted = true
+	})
+	return nil
+}
+
+// DeleteIfExists deletes the section only if it is cached, so that cleanup does not fail at the commit
+// for the section already deleted. For the id not cached, nothing is queued and it returns nil,
+// or *NotFoundError with ErrorIfNotFound.
+func (c *SectionClient) DeleteIfExists(id ID, opts *DeleteOpts) error {
+	if c.Resolve(id) == nil {
+		return opts.notFound("section", id)
+	}
+	return c.Delete(id)
+}
+
+// DeleteKeepTasks moves the cached items of the section out of it to its project, and deletes the section,
+// since deleting a section deletes its items as well. The moves are queued before the delete,
+// so that they are committed first. Subtasks are moved along with their parents, as Move does.
+// The section is fetched if it is not cached.
+func (c *SectionClient) DeleteKeepTasks(ctx context.Context, sectionID ID) error {
+	section := c.Resolve(sectionID)
+	if section == nil {
+		res, err := c.Get(ctx, sectionID)
+		if err != nil {
+			return err
+		}
+		section = &res.Section
+	}
+	var items []Item
+	for _, item := range c.Item.GetAll() {
+		if item.SectionID == sectionID {
+			items = append(items, item)
+		}
+	}
+	for _, item := range items {
+		if containsItem(items, item.ParentID) {
+			continue
+		}
+		if err := c.Item.Move(item.ID, &ItemMoveOpts{ProjectID: section.ProjectID}); err != nil {
+			return err
+		}
+	}
+	return c.Delete(sectionID)
+}
+
+func (c *SectionClient) Archive(id ID) error {
+	c.enqueue("section_archive", map[string]ID{"id": id}, "")
+	c.apply(id, func(section *Section) {
+		section.IsArchived = true
+	})
+	return nil
+}
+
+// Unarchive unarchives the section. The items of the section are archived along with it, and syncs
+// do not return them again, so Commit fetches the project of the section by ProjectClient.GetData
+// after the section is unarchived, which stores the items in the cache. If it fails, Commit returns
+// *RestoreError though the commands are committed.
+func (c *SectionClient) Unarchive(id ID) error {
+	c.enqueue("section_unarchive", map[string]ID{"id": id}, "")
+	c.apply(id, func(section *Section) {
+		section.IsArchived = false
+	})
+	return nil
+}
+
+// restoreItems stores the items of the unarchived sections in the cache, along with their projects.
+func (c *SectionClient) restoreItems(ctx context.Context, ids []ID) error {
+	sections := map[ID][]ID{}
+	var projects []ID
+	for _, id := range ids {
+		section := c.Resolve(id)
+		if section == nil {
+			continue
+		}
+		if _, ok := sections[section.ProjectID]; !ok {
+			projects = append(projects, section.ProjectID)
+		}
+		sections[section.ProjectID] = append(sections[section.ProjectID], id)
+	}
+	for _, projectID := range projects {
+		if _, err := c.Project.GetData(ctx, projectID); err != nil {
+			return &RestoreError{SectionIDs: sections[projectID], Err: err}
+		}
+	}
+	return nil
+}
+
+// apply changes the cached section before the command is committed.
+// The change is rolled back if the command fails on Commit.
+// Nothing is changed with CachePessimistic.
+func (c *SectionClient) apply(id ID, f func(section *Section)) {
+	section := c.cache.resolve(id)
+	if section == nil || !c.optimistic() {
+		return
+	}
+	c.cache.markPending(id)
+	f(section)
+	c.cache.store(*section)
+}
+
+// Reorder updates the orders of the sections, and the section orders of the cached sections.
+// GetAll returns the reordered sections in the new order, in the positions they had.
+func (c *SectionClient) Reorder(sections []Section) error {
+	var args []map[string]interface{}
+	for _, section := range sections {
+		args = append(args, map[string]interface{}{
+			"id":            section.ID,
+			"section_order": section.SectionOrder,
+		})
+	}
+	c.enqueue("section_reorder", map[string][]map[string]interface{}{"sections": args}, "")
+	if c.optimistic() {
+		orders := map[ID]int{}
+		for _, section := range sections {
+			orders[section.ID] = section.SectionOrder
+		}
+		c.cache.reorder(orders)
+	}
+	return nil
+}
+
+type SectionGetResponse struct {
+	Section Section
+	Project Project
+}
+
+func (c *SectionClient) Get(ctx context.Context, id ID) (*SectionGetResponse, error) {
+	values := url.Values{"section_id": {id.String()}}
+	req, err := c.newRequest(ctx, http.MethodGet, pathSectionsGet, values)
+	if err != nil {
+		return nil, err
+	}
+	var out SectionGetResponse
+	res, cached, err := c.doETag(req, &out)
+	if err != nil {
+		return nil, err
+	}
+	if cached {
+		return &out, nil
+	}
+	err = c.decodeBody(res, &out)
+	if err != nil {
+		return nil, err
+	}
+	c.storeETag(req, res, &out)
+	return &out, nil
+}
+
+// Duplicate adds a section with the same name to the project, and copies the cached items of the section into it.
+// Copied items keep their content, priority, labels and hierarchy, but not their completion.
+// The section is fetched if it is not cached.
+func (c *SectionClient) Duplicate(ctx context.Context, sectionID, targetProjectID ID) (*Section, error) {
+	source := c.Resolve(sectionID)
+	if source == nil {
+		res, err := c.Get(ctx, sectionID)
+		if err != nil {
+			return nil, err
+		}
+		source = &res.Section
+	}
+	section, err := NewSection(source.Name, &NewSectionOpts{ProjectID: targetProjectID})
+	if err != nil {
+		return nil, err
+	}
+	if _, err = c.Add(*section); err != nil {
+		return nil, err
+	}
+
+	var items []Item
+	for _, item := range c.Item.GetAll() {
+		if item.SectionID == sectionID {
+			items = append(items, item)
+		}
+	}
+	// add parents before their children, so that children can refer to them.
+	ids := map[ID]ID{}
+	for len(items) > 0 {
+		var rest []Item
+		for _, item := range items {
+			if _, ok := ids[item.ParentID]; !ok && containsItem(items, item.ParentID) {
+				rest = append(rest, item)
+				continue
+			}
+			copied, err := NewItem(item.Content, &NewItemOpts{
+				ProjectID:  targetProjectID,
+				SectionID:  section.ID,
+				ParentID:   ids[item.ParentID],
+				Priority:   item.Priority,
+				ChildOrder: item.ChildOrder,
+				Labels:     item.Labels,
+			})
+			if err != nil {
+				return nil, err
+			}
+			if _, err = c.Item.Add(*copied); err != nil {
+				return nil, err
+			}
+			ids[item.ID] = copied.ID
+		}
+		if len(rest) == len(items) {
+			// a malformed hierarchy with a cycle.
+			for i := range rest {
+				rest[i].ParentID = ""
+			}
+		}
+		items = rest
+	}
+	return section, nil
+}
+
+func containsItem(items []Item, id ID) bool {
+	for _, item := range items {
+		if item.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *SectionClient) GetAll() []Section {
+	return c.cache.getAll()
+}
+
+// GetByProject returns the cached sections of the project ordered by section order.
+// It returns an empty slice if the project has no sections.
+func (c *SectionClient) GetByProject(projectID ID) []Section {
+	res := []Section{}
+	for _, section := range c.GetAll() {
+		if section.ProjectID == projectID {
+			res = append(res, section)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].SectionOrder < res[j].SectionOrder
+	})
+	return res
+}
+
+// EnsureSection returns the cached section of the exact name in the project, or adds a new one if none, as NewSection does.
+// Archived sections are not counted. Sections added but not committed yet are counted, so that EnsureSection
+// does not add duplicates in a batch. The check is only against the cache; see EnsureSectionSynced.
+func (c *SectionClient) EnsureSection(ctx context.Context, projectID ID, name string) (*Section, error) {
+	for _, section := range c.GetByProject(projectID) {
+		if section.Name == name && !section.IsArchived.Bool() {
+			return &section, nil
+		}
+	}
+	section, err := NewSection(name, &NewSectionOpts{ProjectID: projectID})
+	if err != nil {
+		return nil, err
+	}
+	return c.Add(*section)
+}
+
+// EnsureSectionSynced syncs the caches, and then returns the section as EnsureSection does,
+// e.g. when the section may have been added by another client.
+func (c *SectionClient) EnsureSectionSynced(ctx context.Context, projectID ID, name string) (*Section, error) {
+	if err := c.Sync(ctx, []Command{}); err != nil {
+		return nil, err
+	}
+	return c.EnsureSection(ctx, projectID, name)
+}
+
+// Resolve returns a copy of the cached section. Use Update to modify it.
+func (c *SectionClient) Resolve(id ID) *Section {
+	return c.cache.resolve(id)
+}
+
+func (c SectionClient) FindByName(substr string) []Section {
+	return c.cache.findByName(trimSectionPrefix(substr))
+}
+
+// FindByNameInsensitive is the case-insensitive version of FindByName.
+func (c SectionClient) FindByNameInsensitive(substr string) []Section {
+	substr = strings.ToLower(trimSectionPrefix(substr))
+	var res []Section
+	for _, s := range c.GetAll() {
+		if strings.Contains(strings.ToLower(s.Name), substr) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// FindOneByName returns the section whose name matches exactly, ignoring case
+// if there is no case-sensitive match, or the first section containing the name.
+func (c SectionClient) FindOneByName(substr string) *Section {
+	substr = trimSectionPrefix(substr)
+	sections := c.FindByNameInsensitive(substr)
+	for _, section := range sections {
+		if section.Name == substr {
+			return &section
+		}
+	}
+	for _, section := range sections {
+		if strings.EqualFold(section.Name, substr) {
+			return &section
+		}
+	}
+	if len(sections) > 0 {
+		return &sections[0]
+	}
+	return nil
+}
+
+// FindByNameRegex returns the cached sections whose name matches the regular expression.
+func (c SectionClient) FindByNameRegex(pattern string) ([]Section, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	var res []Section
+	for _, s := range c.GetAll() {
+		if re.MatchString(s.Name) {
+			res = append(res, s)
+		}
+	}
+	return res, nil
+}
+
+// ResolveByPath returns the cached section by the path of names, e.g. "Work/In Progress".
+// Names are matched exactly, and nested projects are given as "Work/Team/In Progress",
+// where the first project may be at any level. The error wraps ErrProjectNotFound or
+// ErrSectionNotFound, and ambiguous names are reported as errors rather than guessed.
+func (c SectionClient) ResolveByPath(path string) (*Section, error) {
+	names := strings.Split(path, "/")
+	if len(names) < 2 {
+		return nil, fmt.Errorf("invalid section path %q, require project/section", path)
+	}
+	var projects []Project
+	for _, p := range c.Project.GetAll() {
+		if p.Name == names[0] {
+			projects = append(projects, p)
+		}
+	}
+	for i, name := range names[:len(names)-1] {
+		if i > 0 {
+			var children []Project
+			for _, p := range c.Project.Children(projects[0].ID) {
+				if p.Name == name {
+					children = append(children, p)
+				}
+			}
+			projects = children
+		}
+		switch len(projects) {
+		case 0:
+			return nil, fmt.Errorf("project %q: %w", strings.Join(names[:i+1], "/"), ErrProjectNotFound)
+		case 1:
+		default:
+			return nil, fmt.Errorf("ambiguous project %q, %d projects match", strings.Join(names[:i+1], "/"), len(projects))
+		}
+	}
+
+	project := projects[0]
+	name := names[len(names)-1]
+	var sections []Section
+	for _, s := range c.GetAll() {
+		if s.ProjectID == project.ID && s.Name == name {
+			sections = append(sections, s)
+		}
+	}
+	switch len(sections) {
+	case 0:
+		return nil, fmt.Errorf("section %q in project %q: %w", name, project.Name, ErrSectionNotFound)
+	case 1:
+		return &sections[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous section %q in project %q, %d sections match", name, project.Name, len(sections))
+	}
+}
+
+func trimSectionPrefix(s string) string {
+	if r := []rune(s); len(r) > 0 && string(r[0]) == "#" {
+		return string(r[1:])
+	}
+	return s
+}
+
+type sectionCache struct {
+	cache *[]Section
+	// index maps ids to positions in cache.
+	index map[ID]int
+	// pending holds sections before local changes by id, or nil for sections added locally.
+	pending map[ID]*Section
+	mu      sync.RWMutex
+	notify  *notifier
+}
+
+func (c *sectionCache) getAll() []Section {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	res := make([]Section, len(*c.cache))
+	copy(res, *c.cache)
+	return res
+}
+
+// findByName returns copies of the cached sections whose name contains substr,
+// without copying the others.
+func (c *sectionCache) findByName(substr string) []Section {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var res []Section
+	for _, s := range *c.cache {
+		if strings.Contains(s.Name, substr) {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// resolve returns a copy of the cached section, so that it is safe to read
+// while the cache is updated. Modifying it does not affect the cache.
+func (c *sectionCache) resolve(id ID) *Section {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	i, ok := c.lookup(id)
+	if !ok {
+		return nil
+	}
+	res := (*c.cache)[i]
+	return &res
+}
+
+// lookup returns the position of the section in the cache. The caller must hold the lock.
+// The cache is scanned if it is not indexed yet.
+func (c *sectionCache) lookup(id ID) (int, bool) {
+	if c.index != nil {
+		i, ok := c.index[id]
+		return i, ok
+	}
+	for i, section := range *c.cache {
+		if section.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// set swaps the cache for the sections and indexes them. The caller must hold the lock.
+func (c *sectionCache) set(sections []Section) {
+	c.cache = &sections
+	c.index = indexSections(sections)
+}
+
+func indexSections(sections []Section) map[ID]int {
+	res := make(map[ID]int, len(sections))
+	for i, section := range sections {
+		res[section.ID] = i
+	}
+	return res
+}
+
+func (c *sectionCache) store(section Section) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("sections")
+	i, ok := c.lookup(section.ID)
+	switch {
+	case section.IsDeleted.Bool():
+		if ok {
+			c.removeAt(i)
+		}
+	case ok:
+		(*c.cache)[i] = section
+	default:
+		*c.cache = append(*c.cache, section)
+		if c.index != nil {
+			c.index[section.ID] = len(*c.cache) - 1
+		}
+	}
+}
+
+func (c *sectionCache) remove(section Section) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("sections")
+	if i, ok := c.lookup(section.ID); ok {
+		c.removeAt(i)
+	}
+}
+
+// removeAt removes the section at the position, and shifts the positions of the following ones in the index.
+// The caller must hold the lock.
+func (c *sectionCache) removeAt(i int) {
+	sections := *c.cache
+	id := sections[i].ID
+	copy(sections[i:], sections[i+1:])
+	sections = sections[:len(sections)-1]
+	*c.cache = sections
+	if c.index != nil {
+		delete(c.index, id)
+		for j := i; j < len(sections); j++ {
+			c.index[sections[j].ID] = j
+		}
+	}
+}
+
+func (c *sectionCache) replaceTempIDs(mapping map[ID]ID) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("sections")
+	res := make([]Section, len(*c.cache))
+	copy(res, *c.cache)
+	for i, section := range res {
+		if id, ok := mapping[section.ID]; ok {
+			res[i].ID = id
+		}
+		res[i].ProjectID = mapTempID(section.ProjectID, mapping)
+	}
+	c.set(res)
+}
+
+// replace swaps the cache for the given sections at once.
+// Cached sections with temporary ids are kept, since they are not committed yet.
+func (c *sectionCache) replace(sections []Section) {
+	var res []Section
+	for _, section := range sections {
+		if !section.IsDeleted.Bool() {
+			res = append(res, section)
+		}
+	}
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("sections")
+	for _, section := range *c.cache {
+		if IsTempID(section.ID) {
+			res = append(res, section)
+		}
+	}
+	c.set(res)
+}
+
+// reorder sets the section orders of the cached sections, and sorts them by the new orders
+// in the positions they had. The other sections are kept in place.
+func (c *sectionCache) reorder(orders map[ID]int) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("sections")
+	var positions []int
+	var sections []Section
+	for i, section := range *c.cache {
+		if order, ok := orders[section.ID]; ok {
+			section.SectionOrder = order
+			positions = append(positions, i)
+			sections = append(sections, section)
+		}
+	}
+	sort.SliceStable(sections, func(i, j int) bool {
+		return sections[i].SectionOrder < sections[j].SectionOrder
+	})
+	for k, i := range positions {
+		(*c.cache)[i] = sections[k]
+		if c.index != nil {
+			c.index[sections[k].ID] = i
+		}
+	}
+}
+
+// reset sets the cache to the given sections, dropping local changes.
+func (c *sectionCache) reset(sections []Section) {
+	res := make([]Section, len(sections))
+	copy(res, sections)
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("sections")
+	c.set(res)
+	c.pending = nil
+}
+
+// synced returns a copy of the section as of the last sync, before local changes.
+func (c *sectionCache) synced(id ID) *Section {
+	c.mu.RLock()
+	original, pending := c.pending[id]
+	c.mu.RUnlock()
+	if !pending {
+		return c.resolve(id)
+	}
+	if original == nil {
+		return nil
+	}
+	res := *original
+	return &res
+}
+
+// markPending keeps the section before the first local change, to roll it back.
+func (c *sectionCache) markPending(id ID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pending[id]; ok {
+		return
+	}
+	if c.pending == nil {
+		c.pending = map[ID]*Section{}
+	}
+	c.pending[id] = nil
+	if i, ok := c.lookup(id); ok {
+		s := (*c.cache)[i]
+		c.pending[id] = &s
+	}
+}
+
+// settle forgets the local change of the section committed successfully,
+// or rolls it back if the command failed.
+func (c *sectionCache) settle(id ID, ok bool) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("sections")
+	original, pending := c.pending[id]
+	if !pending {
+		return
+	}
+	delete(c.pending, id)
+	if ok {
+		return
+	}
+	var res []Section
+	for _, s := range *c.cache {
+		if s.ID != id {
+			res = append(res, s)
+		}
+	}
+	if original != nil {
+		res = append(res, *original)
+	}
+	c.set(res)
+}
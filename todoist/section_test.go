@@ -0,0 +1,711 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSectionCache_Resolve(t *testing.T) {
+	cache := &sectionCache{cache: &[]Section{}}
+	section := Section{Name: "before"}
+	section.ID = "1"
+	cache.store(section)
+
+	resolved := cache.resolve("1")
+	if resolved == nil || resolved.Name != "before" {
+		t.Fatalf("Expect %v, but got %v", section, resolved)
+	}
+	resolved.Name = "after"
+	if s := cache.resolve("1"); s.Name != "before" {
+		t.Errorf("Expect cache not to be modified, but got %s", s.Name)
+	}
+	if s := cache.getAll()[0]; s.Name != "before" {
+		t.Errorf("Expect cache not to be modified, but got %s", s.Name)
+	}
+	if s := cache.resolve("2"); s != nil {
+		t.Errorf("Expect nil, but got %v", s)
+	}
+}
+
+func TestSectionClient_FindByNameInsensitive(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for i, name := range []string{"TODO later", "Todo", "todo", "Done"} {
+		section := Section{Name: name}
+		section.ID = ID(strconv.Itoa(i + 1))
+		client.Section.cache.store(section)
+	}
+
+	if sections := client.Section.FindByName("todo"); len(sections) != 1 {
+		t.Errorf("Expect %d sections, but got %v", 1, sections)
+	}
+	if sections := client.Section.FindByNameInsensitive("#todo"); len(sections) != 3 {
+		t.Errorf("Expect %d sections, but got %v", 3, sections)
+	}
+	tests := []struct {
+		s string
+		v string
+	}{
+		{"todo", "todo"},
+		{"#Todo", "Todo"},
+		{"TODO", "Todo"},
+		{"later", "TODO later"},
+		{"DONE", "Done"},
+	}
+	for _, test := range tests {
+		if s := client.Section.FindOneByName(test.s); s == nil || s.Name != test.v {
+			t.Errorf("Expect %s, but got %v", test.v, s)
+		}
+	}
+	if s := client.Section.FindOneByName("none"); s != nil {
+		t.Errorf("Expect nil, but got %v", s)
+	}
+}
+
+func TestSectionClient_Move(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+
+	if err := client.Section.Move("1", "2"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	values, err := client.DryRun()
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(values) != 1 {
+		t.Fatalf("Expect %d request, but got %d", 1, len(values))
+	}
+	expect := `[{"type":"section_move","args":{"id":"1","project_id":"2"},"uuid":"` + string(client.queue[0].UUID) + `","temp_id":null}]`
+	if v := values[0].Get("commands"); v != expect {
+		t.Errorf("Expect %s, but got %s", expect, v)
+	}
+	if len(client.queue) != 1 {
+		t.Errorf("Expect queue to be kept, but got %d command(s)", len(client.queue))
+	}
+}
+
+func TestSectionClient_AddTwice(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+
+	section, _ := NewSection("before", &NewSectionOpts{ProjectID: "1"})
+	client.Section.Add(*section)
+	section.Name = "after"
+	client.Section.Add(*section)
+
+	if len(client.queue) != 1 {
+		t.Fatalf("Expect %d command, but got %d", 1, len(client.queue))
+	}
+	if s := client.queue[0].Args.(Section); s.Name != "after" {
+		t.Errorf("Expect the latter command, but got %s", s.Name)
+	}
+	if sections := client.Section.GetAll(); len(sections) != 1 || sections[0].Name != "after" {
+		t.Errorf("Expect a section, but got %v", sections)
+	}
+}
+
+func TestSectionClient_FindByNameRegex(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for i, name := range []string{"Work", "Workshop", "Homework"} {
+		section := Section{Name: name}
+		section.ID = ID(strconv.Itoa(i + 1))
+		client.Section.cache.store(section)
+	}
+
+	sections, err := client.Section.FindByNameRegex("^Work$")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(sections) != 1 || sections[0].Name != "Work" {
+		t.Errorf("Unexpect sections: %v", sections)
+	}
+	if _, err := client.Section.FindByNameRegex("(Work"); err == nil {
+		t.Error("Expect error for invalid pattern, but got nil")
+	}
+}
+
+func TestSectionClient_Rollback(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		status := map[UUID]interface{}{}
+		for _, command := range commands {
+			if command.Type == "section_move" {
+				status[command.UUID] = "ok"
+			} else {
+				status[command.UUID] = map[string]interface{}{"error_code": 20, "error": "Section not found"}
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token":  "next-token",
+			"sync_status": status,
+		})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	for _, id := range []ID{"1", "2"} {
+		section := Section{Name: "section", ProjectID: "10"}
+		section.ID = id
+		client.Section.cache.store(section)
+	}
+
+	client.Section.Archive("1")
+	client.Section.Move("2", "20")
+	added, _ := NewSection("added", &NewSectionOpts{ProjectID: "10"})
+	client.Section.Add(*added)
+	if s := client.Section.Resolve("1"); !s.IsArchived {
+		t.Error("Expect section to be archived before commit")
+	}
+	if s := client.Section.Resolve("2"); s.ProjectID != "20" {
+		t.Errorf("Expect %s before commit, but got %s", "20", s.ProjectID)
+	}
+
+	err := client.Commit(context.Background())
+	var commitErr *CommitError
+	if !errors.As(err, &commitErr) || len(commitErr.Errors) != 2 {
+		t.Fatalf("Expect 2 command errors, but got %v", err)
+	}
+	if s := client.Section.Resolve("1"); s == nil || s.IsArchived {
+		t.Errorf("Expect archive to be rolled back, but got %v", s)
+	}
+	if s := client.Section.Resolve("2"); s == nil || s.ProjectID != "20" {
+		t.Errorf("Expect move to be kept, but got %v", s)
+	}
+	if s := client.Section.Resolve(added.ID); s != nil {
+		t.Errorf("Expect failed add to be rolled back, but got %v", s)
+	}
+}
+
+func TestSectionClient_Duplicate(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	source := Section{Name: "source", ProjectID: "1"}
+	source.ID = "10"
+	client.Section.cache.store(source)
+	for _, i := range []Item{
+		{SectionID: "10", ParentID: "101", Content: "child", Checked: true},
+		{SectionID: "10", Content: "parent", Priority: P1, Labels: []ID{"5"}},
+		{SectionID: "11", Content: "other"},
+	} {
+		i.ID = ID(strconv.Itoa(100 + len(client.Item.GetAll())))
+		client.Item.cache.store(i)
+	}
+
+	section, err := client.Section.Duplicate(context.Background(), "10", "2")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if section.Name != "source" || section.ProjectID != "2" || !IsTempID(section.ID) {
+		t.Errorf("Unexpect section: %v", section)
+	}
+	var types []string
+	for _, command := range client.queue {
+		types = append(types, command.Type)
+	}
+	if len(types) != 3 || types[0] != "section_add" || types[1] != "item_add" || types[2] != "item_add" {
+		t.Fatalf("Unexpect commands: %v", types)
+	}
+	parent := client.queue[1].Args.(Item)
+	child := client.queue[2].Args.(Item)
+	if parent.Content != "parent" || parent.SectionID != section.ID || parent.Priority != P1 || len(parent.Labels) != 1 {
+		t.Errorf("Unexpect parent: %v", parent)
+	}
+	if child.Content != "child" || child.ParentID != parent.ID || child.Checked {
+		t.Errorf("Unexpect child: %v", child)
+	}
+}
+
+func TestSectionClient_UpdateWithOpts(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	section := Section{Name: "before", ProjectID: "10", SectionOrder: 1}
+	section.ID = "1"
+	client.Section.cache.store(section)
+
+	base := *client.Section.Resolve("1")
+	modified := base
+	modified.Name = "after"
+	if _, err := client.Section.UpdateWithOpts(modified, &SectionUpdateOpts{Base: &base, ChangedOnly: true, Strict: true}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	b, _ := json.Marshal(client.Pending()[0].Args)
+	if expect := `{"id":"1","name":"after"}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	client.Discard()
+
+	// someone else moves the section, and it is synced.
+	section.SectionOrder = 2
+	client.Section.cache.store(section)
+	_, err := client.Section.UpdateWithOpts(modified, &SectionUpdateOpts{Base: &base, Strict: true})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || conflict.ID != "1" {
+		t.Errorf("Expect conflict error, but got %v", err)
+	}
+	if len(client.Pending()) != 0 {
+		t.Errorf("Expect empty queue, but got %d command(s)", len(client.Pending()))
+	}
+	if _, err = client.Section.UpdateWithOpts(modified, &SectionUpdateOpts{Strict: true}); err == nil {
+		t.Error("Expect error without base")
+	}
+	if _, err = client.Section.UpdateWithOpts(modified, nil); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if pending := client.Pending(); len(pending) != 1 || pending[0].Type != "section_update" {
+		t.Errorf("Expect the update, but got %v", pending)
+	}
+}
+
+func TestSectionClient_UpdateFields(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	section := Section{Name: "before", ProjectID: "10"}
+	section.ID = "1"
+	client.Section.cache.store(section)
+
+	if err := client.Section.UpdateFields("1", map[string]interface{}{"project_id": "20"}); err == nil {
+		t.Error("Expect error for project_id")
+	}
+	if err := client.Section.UpdateFields("1", map[string]interface{}{"name": "after", "collapsed": IntBool(true)}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	pending := client.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("Expect %d command, but got %d", 1, len(pending))
+	}
+	b, _ := json.Marshal(pending[0].Args)
+	if expect := `{"collapsed":true,"id":"1","name":"after"}`; string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	s := client.Section.Resolve("1")
+	if s.Name != "after" || !s.Collapsed.Bool() || s.ProjectID != "10" {
+		t.Errorf("Unexpect section: %v", s)
+	}
+}
+
+func TestSectionClient_Reorder(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for i, id := range []ID{"1", "2", "3", "4"} {
+		section := Section{Name: "section", ProjectID: "10", SectionOrder: i + 1}
+		section.ID = id
+		client.Section.cache.store(section)
+	}
+
+	var sections []Section
+	for i, id := range []ID{"3", "1", "4"} {
+		section := Section{SectionOrder: i + 1}
+		section.ID = id
+		sections = append(sections, section)
+	}
+	if err := client.Section.Reorder(sections); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	b, _ := json.Marshal(client.Pending()[0].Args)
+	expect := `{"sections":[{"id":"3","section_order":1},{"id":"1","section_order":2},{"id":"4","section_order":3}]}`
+	if string(b) != expect {
+		t.Errorf("Expect %s, but got %s", expect, string(b))
+	}
+	var ids []ID
+	for _, section := range client.Section.GetAll() {
+		ids = append(ids, section.ID)
+	}
+	// the section not reordered is kept in place.
+	if expect := []ID{"3", "2", "1", "4"}; !reflect.DeepEqual(ids, expect) {
+		t.Errorf("Expect %v, but got %v", expect, ids)
+	}
+	if s := client.Section.Resolve("1"); s.SectionOrder != 2 {
+		t.Errorf("Expect %d, but got %d", 2, s.SectionOrder)
+	}
+}
+
+func TestSectionClient_ResolveByPath(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for _, p := range []struct {
+		id       ID
+		name     string
+		parentID ID
+	}{{"1", "Work", ""}, {"2", "Team", "1"}, {"3", "Home", ""}, {"4", "Home", ""}} {
+		project := Project{Name: p.name, ParentID: p.parentID}
+		project.ID = p.id
+		client.Project.cache.store(project)
+	}
+	for _, s := range []struct {
+		id        ID
+		name      string
+		projectID ID
+	}{{"10", "In Progress", "1"}, {"11", "In Progress", "2"}, {"12", "Done", "2"}, {"13", "Done", "2"}} {
+		section := Section{Name: s.name, ProjectID: s.projectID}
+		section.ID = s.id
+		client.Section.cache.store(section)
+	}
+
+	tests := []struct {
+		path string
+		id   ID
+	}{
+		{"Work/In Progress", "10"},
+		{"Work/Team/In Progress", "11"},
+		{"Team/In Progress", "11"},
+	}
+	for _, test := range tests {
+		if s, err := client.Section.ResolveByPath(test.path); err != nil || s.ID != test.id {
+			t.Errorf("Expect %s, but got %v (%v)", test.id, s, err)
+		}
+	}
+	if _, err := client.Section.ResolveByPath("Private/In Progress"); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("Expect %s, but got %v", ErrProjectNotFound, err)
+	}
+	if _, err := client.Section.ResolveByPath("Work/In"); !errors.Is(err, ErrSectionNotFound) {
+		t.Errorf("Expect %s, but got %v", ErrSectionNotFound, err)
+	}
+	for _, path := range []string{"Home/Done", "Work/Team/Done", "Work"} {
+		if _, err := client.Section.ResolveByPath(path); err == nil || errors.Is(err, ErrProjectNotFound) || errors.Is(err, ErrSectionNotFound) {
+			t.Errorf("Expect error for %s, but got %v", path, err)
+		}
+	}
+}
+
+func TestSectionClient_GetByProject(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for i, order := range []int{3, 1, 2} {
+		section := Section{Name: strconv.Itoa(order), ProjectID: "10", SectionOrder: order}
+		section.ID = ID(strconv.Itoa(i + 1))
+		client.Section.cache.store(section)
+	}
+	other := Section{Name: "other", ProjectID: "20"}
+	other.ID = "4"
+	client.Section.cache.store(other)
+
+	var names []string
+	for _, section := range client.Section.GetByProject("10") {
+		names = append(names, section.Name)
+	}
+	if expect := []string{"1", "2", "3"}; !reflect.DeepEqual(names, expect) {
+		t.Errorf("Expect %v, but got %v", expect, names)
+	}
+	if sections := client.Section.GetByProject("30"); sections == nil || len(sections) != 0 {
+		t.Errorf("Expect empty slice, but got %#v", sections)
+	}
+}
+
+func TestNewSection_NilOpts(t *testing.T) {
+	section, err := NewSection("Inbox", nil)
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if !section.ProjectID.IsZero() || section.Name != "Inbox" || !IsTempID(section.ID) {
+		t.Errorf("Unexpect section: %v", section)
+	}
+
+	if _, err = NewItem("item", nil); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if _, err = NewProject("project", nil); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if _, err = NewLabel("label", nil); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if _, err = NewFilter("filter", "today", nil); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if _, err = NewNote("1", "note", nil); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	if _, err = NewRelativeReminder("1", 30, nil); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+}
+
+func TestSectionClient_UpdateEmptyName(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	section := Section{Name: "before", ProjectID: "1"}
+	section.ID = "10"
+	client.Section.cache.store(section)
+
+	for _, name := range []string{"", " \t\n"} {
+		section.Name = name
+		if _, err := client.Section.Update(section); err == nil {
+			t.Errorf("Expect error for %q, but got nil", name)
+		}
+		if _, err := client.Section.UpdateWithOpts(section, &SectionUpdateOpts{}); err == nil {
+			t.Errorf("Expect error for %q, but got nil", name)
+		}
+		if err := client.Section.UpdateFields("10", map[string]interface{}{"name": name}); err == nil {
+			t.Errorf("Expect error for %q, but got nil", name)
+		}
+		if err := client.Project.UpdateFields("1", map[string]interface{}{"name": name}); err == nil {
+			t.Errorf("Expect error for %q, but got nil", name)
+		}
+		if _, err := client.Project.Update(Project{Name: name}); err == nil {
+			t.Errorf("Expect error for %q, but got nil", name)
+		}
+		if _, err := client.Label.Update(Label{Name: name}); err == nil {
+			t.Errorf("Expect error for %q, but got nil", name)
+		}
+		if err := client.Label.Rename("2", name); err == nil {
+			t.Errorf("Expect error for %q, but got nil", name)
+		}
+		if _, err := client.Filter.Update(Filter{Name: name, Query: "today"}); err == nil {
+			t.Errorf("Expect error for %q, but got nil", name)
+		}
+	}
+	if len(client.queue) != 0 {
+		t.Errorf("Expect no command, but got %v", client.queue)
+	}
+	if cached := client.Section.Resolve("10"); cached.Name != "before" {
+		t.Errorf("Expect %s, but got %s", "before", cached.Name)
+	}
+	if err := client.Section.UpdateFields("10", map[string]interface{}{"collapsed": IntBool(true)}); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+}
+
+func TestSectionClient_EnsureSection(t *testing.T) {
+	var syncs int
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		syncs++
+		w.Write([]byte(`{"sync_token": "next-token", "sections": [{"id": 3, "name": "Later", "project_id": 1}]}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	for _, section := range []Section{
+		{Entity: Entity{ID: "1"}, Name: "Done", ProjectID: "1", IsArchived: true},
+		{Entity: Entity{ID: "2"}, Name: "Doing", ProjectID: "1"},
+		{Entity: Entity{ID: "4"}, Name: "Done", ProjectID: "2"},
+	} {
+		client.Section.cache.store(section)
+	}
+
+	section, err := client.Section.EnsureSection(context.Background(), "1", "Doing")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if section.ID != "2" || len(client.Pending()) != 0 {
+		t.Errorf("Expect the existing section, but got %v", section)
+	}
+	added, err := client.Section.EnsureSection(context.Background(), "1", "Done")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if !IsTempID(added.ID) || added.ProjectID != "1" {
+		t.Errorf("Expect a new section, but got %v", added)
+	}
+	again, err := client.Section.EnsureSection(context.Background(), "1", "Done")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if again.ID != added.ID || len(client.Pending()) != 1 {
+		t.Errorf("Expect the queued section, but got %v with %d command(s)", again, len(client.Pending()))
+	}
+	if _, err = client.Section.EnsureSection(context.Background(), "1", ""); err == nil {
+		t.Error("Expect error for empty name, but got nil")
+	}
+
+	synced, err := client.Section.EnsureSectionSynced(context.Background(), "1", "Later")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if syncs != 1 || synced.ID != "3" || len(client.Pending()) != 1 {
+		t.Errorf("Expect the synced section, but got %v", synced)
+	}
+}
+
+func TestSectionClient_DeleteIfExists(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpect request: %s", r.URL)
+	})
+	defer teardown()
+	client.Section.cache.store(Section{Entity: Entity{ID: "1"}, Name: "section", ProjectID: "10"})
+
+	if err := client.Section.DeleteIfExists("1", nil); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if len(client.queue) != 1 || client.queue[0].Type != "section_delete" {
+		t.Fatalf("Unexpect queue: %v", client.queue)
+	}
+	if err := client.Section.DeleteIfExists("1", nil); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	err := client.Section.DeleteIfExists("2", &DeleteOpts{ErrorIfNotFound: true})
+	if !errors.Is(err, ErrSectionNotFound) {
+		t.Errorf("Expect %s, but got %v", ErrSectionNotFound, err)
+	}
+	if len(client.queue) != 1 {
+		t.Errorf("Unexpect queue: %v", client.queue)
+	}
+}
+
+func TestSectionClient_UnarchiveRestoresItems(t *testing.T) {
+	var paths []string
+	fail := false
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/sync":
+			var commands []Command
+			if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+				t.Errorf("Unexpect error: %s", err)
+			}
+			status := map[UUID]string{}
+			for _, command := range commands {
+				status[command.UUID] = "ok"
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"sync_token": "next-token", "sync_status": status})
+		case "/projects/get_data":
+			if id := r.FormValue("project_id"); id != "10" {
+				t.Errorf("Expect %s, but got %s", "10", id)
+			}
+			if fail {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte(`{"project": {"id": 10, "name": "project"}, "items": [
+				{"id": 100, "project_id": 10, "section_id": 1, "content": "first"},
+				{"id": 101, "project_id": 10, "section_id": 1, "content": "second"},
+				{"id": 102, "project_id": 10, "content": "other"}
+			]}`))
+		default:
+			t.Errorf("Unexpect request: %s", r.URL)
+		}
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	client.Section.cache.store(Section{Entity: Entity{ID: "1"}, Name: "section", ProjectID: "10"})
+	client.Item.cache.store(Item{Entity: Entity{ID: "100"}, ProjectID: "10", SectionID: "1", Content: "first"})
+	client.Item.cache.store(Item{Entity: Entity{ID: "101"}, ProjectID: "10", SectionID: "1", Content: "second"})
+
+	client.Section.Archive("1")
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	// the server archives the items along with the section, and syncs drop them.
+	client.Item.cache.remove(Item{Entity: Entity{ID: "100"}})
+	client.Item.cache.remove(Item{Entity: Entity{ID: "101"}})
+
+	client.Section.Unarchive("1")
+	if err := client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if expect := []string{"/sync", "/sync", "/projects/get_data"}; !reflect.DeepEqual(paths, expect) {
+		t.Errorf("Expect %v, but got %v", expect, paths)
+	}
+	if section := client.Section.Resolve("1"); section == nil || section.IsArchived {
+		t.Errorf("Expect unarchived section, but got %v", section)
+	}
+	if client.Item.Resolve("100") == nil || client.Item.Resolve("101") == nil {
+		t.Errorf("Expect items of the section to be stored, but got %v", client.Item.GetAll())
+	}
+	if client.Item.Resolve("102") == nil {
+		t.Error("Expect items of the project to be stored")
+	}
+
+	fail = true
+	client.Section.Unarchive("1")
+	err := client.Commit(context.Background())
+	var restoreErr *RestoreError
+	var apiErr *APIError
+	if !errors.As(err, &restoreErr) || !reflect.DeepEqual(restoreErr.SectionIDs, []ID{"1"}) || !errors.As(err, &apiErr) {
+		t.Errorf("Expect RestoreError, but got %v", err)
+	}
+	if len(client.Pending()) != 0 {
+		t.Errorf("Expect the committed command not to be queued, but got %v", client.Pending())
+	}
+}
+
+func TestSectionClient_DeleteKeepTasks(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpect request: %s", r.URL)
+	})
+	defer teardown()
+	client.Section.cache.store(Section{Entity: Entity{ID: "1"}, Name: "section", ProjectID: "10"})
+	for _, item := range []Item{
+		{Entity: Entity{ID: "100"}, ProjectID: "10", SectionID: "1"},
+		{Entity: Entity{ID: "101"}, ProjectID: "10", SectionID: "1", ParentID: "100"},
+		{Entity: Entity{ID: "102"}, ProjectID: "10", SectionID: "1"},
+		{Entity: Entity{ID: "103"}, ProjectID: "10", SectionID: "2"},
+	} {
+		client.Item.cache.store(item)
+	}
+
+	if err := client.Section.DeleteKeepTasks(context.Background(), "1"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	var commands []string
+	for _, command := range client.Pending() {
+		b, _ := json.Marshal(command.Args)
+		commands = append(commands, command.Type+" "+string(b))
+	}
+	expect := []string{
+		`item_move {"id":"100","project_id":"10"}`,
+		`item_move {"id":"102","project_id":"10"}`,
+		`section_delete {"id":"1"}`,
+	}
+	if !reflect.DeepEqual(commands, expect) {
+		t.Errorf("Expect %v, but got %v", expect, commands)
+	}
+	for _, id := range []ID{"100", "101", "102"} {
+		if item := client.Item.Resolve(id); item.SectionID != "" || item.ProjectID != "10" {
+			t.Errorf("Expect item %s out of the section, but got %v", id, item)
+		}
+	}
+	if item := client.Item.Resolve("101"); item.ParentID != "100" {
+		t.Errorf("Expect %s, but got %s", "100", item.ParentID)
+	}
+}
+
+func TestSectionClient_DeleteKeepTasksRollback(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		var commands []Command
+		if err := json.Unmarshal([]byte(r.FormValue("commands")), &commands); err != nil {
+			t.Errorf("Unexpect error: %s", err)
+		}
+		status := map[UUID]interface{}{}
+		for _, command := range commands {
+			status[command.UUID] = map[string]interface{}{"error_code": 20, "error": "Section not found"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sync_token":  "next-token",
+			"sync_status": status,
+		})
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	client.Section.cache.store(Section{Entity: Entity{ID: "1"}, Name: "section", ProjectID: "10"})
+	for _, item := range []Item{
+		{Entity: Entity{ID: "100"}, ProjectID: "10", SectionID: "1"},
+		{Entity: Entity{ID: "101"}, ProjectID: "10", SectionID: "1", ParentID: "100"},
+		{Entity: Entity{ID: "102"}, ProjectID: "10", SectionID: "1", ParentID: "101"},
+	} {
+		client.Item.cache.store(item)
+	}
+
+	if err := client.Section.DeleteKeepTasks(context.Background(), "1"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if item := client.Item.Resolve("102"); item.SectionID != "" {
+		t.Errorf("Expect the subtask out of the section before commit, but got %v", item)
+	}
+	var commitErr *CommitError
+	if err := client.Commit(context.Background()); !errors.As(err, &commitErr) {
+		t.Fatalf("Expect CommitError, but got %v", err)
+	}
+	for _, id := range []ID{"100", "101", "102"} {
+		if item := client.Item.Resolve(id); item == nil || item.SectionID != "1" {
+			t.Errorf("Expect item %s to be rolled back, but got %v", id, item)
+		}
+	}
+}
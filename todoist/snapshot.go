@@ -0,0 +1,87 @@
+package todoist
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// snapshotVersion is the schema version of snapshots. Increase it on incompatible changes.
+const snapshotVersion = 1
+
+type snapshot struct {
+	Version int `json:"version"`
+	// State is the state of the last sync, which Discard reverts the caches to.
+	State *SyncState `json:"state"`
+	// Caches is the resources in the caches, including local changes.
+	Caches    *SyncState        `json:"caches"`
+	SyncToken string            `json:"sync_token"`
+	Queue     []snapshotCommand `json:"queue"`
+	TempIDs   map[ID]ID         `json:"temp_ids,omitempty"`
+}
+
+// snapshotCommand keeps args as is, so that ids in them are not decoded into numbers.
+type snapshotCommand struct {
+	Type   string          `json:"type"`
+	Args   json.RawMessage `json:"args"`
+	UUID   UUID            `json:"uuid"`
+	TempID ID              `json:"temp_id"`
+}
+
+// Snapshot returns the caches, the sync token and the queued commands encoded in JSON,
+// e.g. to save them for offline use. LoadSnapshot restores them.
+func (c *Client) Snapshot() ([]byte, error) {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	s := snapshot{
+		Version:   snapshotVersion,
+		State:     c.syncState,
+		Caches:    c.cachedState(),
+		SyncToken: c.SyncToken(),
+	}
+	c.mu.Lock()
+	queue := make([]Command, len(c.queue))
+	copy(queue, c.queue)
+	s.TempIDs = map[ID]ID{}
+	for k, v := range c.tempIDs {
+		s.TempIDs[k] = v
+	}
+	c.mu.Unlock()
+	for _, command := range queue {
+		args, err := json.Marshal(command.Args)
+		if err != nil {
+			return nil, err
+		}
+		s.Queue = append(s.Queue, snapshotCommand{Type: command.Type, Args: args, UUID: command.UUID, TempID: command.TempID})
+	}
+	return json.Marshal(s)
+}
+
+// LoadSnapshot replaces the caches, the sync token and the queued commands with the snapshot.
+// It returns an error and keeps the client as is if the snapshot is of another schema version.
+func (c *Client) LoadSnapshot(data []byte) error {
+	var s snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	if s.Version != snapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d, expect %d", s.Version, snapshotVersion)
+	}
+	if s.State == nil || s.Caches == nil {
+		return fmt.Errorf("invalid snapshot, no state")
+	}
+	queue := []Command{}
+	for _, command := range s.Queue {
+		queue = append(queue, Command{Type: command.Type, Args: command.Args, UUID: command.UUID, TempID: command.TempID})
+	}
+
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	c.mu.Lock()
+	c.queue = queue
+	c.tempIDs = s.TempIDs
+	c.mu.Unlock()
+	c.SetSyncToken(s.SyncToken)
+	c.syncState = s.State
+	c.resetCaches(s.Caches)
+	return nil
+}
@@ -0,0 +1,103 @@
+package todoist
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestClient_Snapshot(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	client.SetSyncToken("token")
+	project := Project{Name: "synced"}
+	project.ID = "1"
+	client.Project.cache.store(project)
+	section, _ := NewSection("local", &NewSectionOpts{ProjectID: "12345678901234567"})
+	client.Section.Add(*section)
+
+	data, err := client.Snapshot()
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+
+	restored, teardown2 := newTestClient(t, nil)
+	defer teardown2()
+	if err = restored.LoadSnapshot(data); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if restored.SyncToken() != "token" {
+		t.Errorf("Expect %s, but got %s", "token", restored.SyncToken())
+	}
+	if restored.Project.Resolve("1") == nil || restored.Section.Resolve(section.ID) == nil {
+		t.Error("Expect caches to be restored")
+	}
+	pending := restored.Pending()
+	if len(pending) != 1 || pending[0].Type != "section_add" || pending[0].TempID != section.ID {
+		t.Fatalf("Unexpect queue: %v", pending)
+	}
+	b, _ := json.Marshal(pending[0].Args)
+	var args struct {
+		ProjectID ID `json:"project_id"`
+	}
+	if err = json.Unmarshal(b, &args); err != nil || args.ProjectID != "12345678901234567" {
+		t.Errorf("Expect project id to be kept, but got %s", string(b))
+	}
+
+	var s map[string]interface{}
+	json.Unmarshal(data, &s)
+	s["version"] = snapshotVersion + 1
+	data, _ = json.Marshal(s)
+	if err = restored.LoadSnapshot(data); err == nil {
+		t.Error("Expect error for version")
+	}
+}
+
+func TestClient_SnapshotCollapsed(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	client.Item.cache.store(Item{Entity: Entity{ID: "1"}, Content: "parent"})
+	client.Section.cache.store(Section{Entity: Entity{ID: "2"}, Name: "section", ProjectID: "3"})
+	client.Project.cache.store(Project{Entity: Entity{ID: "3"}, Name: "project", Collapsed: true})
+
+	if err := client.Item.SetCollapsed("1", true); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Section.SetCollapsed("2", true); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err := client.Project.SetCollapsed("3", false); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	var types []string
+	for _, command := range client.Pending() {
+		types = append(types, command.Type)
+		b, _ := json.Marshal(command.Args)
+		var args map[string]interface{}
+		json.Unmarshal(b, &args)
+		if _, ok := args["collapsed"]; !ok || len(args) != 2 {
+			t.Errorf("Unexpect args: %s", b)
+		}
+	}
+	if len(types) != 3 || types[0] != "item_update" || types[1] != "section_update" || types[2] != "project_update" {
+		t.Errorf("Unexpect commands: %v", types)
+	}
+
+	data, err := client.Snapshot()
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	restored, teardown2 := newTestClient(t, nil)
+	defer teardown2()
+	if err = restored.LoadSnapshot(data); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if item := restored.Item.Resolve("1"); item == nil || !item.Collapsed.Bool() {
+		t.Errorf("Expect the item to be collapsed, but got %v", item)
+	}
+	if section := restored.Section.Resolve("2"); section == nil || !section.Collapsed.Bool() {
+		t.Errorf("Expect the section to be collapsed, but got %v", section)
+	}
+	if project := restored.Project.Resolve("3"); project == nil || project.Collapsed.Bool() {
+		t.Errorf("Expect the project to be expanded, but got %v", project)
+	}
+}
@@ -0,0 +1,78 @@
+package todoist
+
+import (
+	"sort"
+	"strings"
+)
+
+// SortKey specifies the order of GetAllSorted.
+type SortKey int
+
+const (
+	// SortByName sorts by the name, or the content of items, ignoring case.
+	SortByName SortKey = iota
+	// SortByOrder sorts by the order in the parent, i.e. child_order or section_order.
+	SortByOrder
+	// SortByID sorts by the id. Ids assigned by the server are compared as numbers.
+	SortByID
+)
+
+// lessID compares numeric ids as numbers, and the others as strings.
+func lessID(a, b ID) bool {
+	if IsTempID(a) || IsTempID(b) || len(a) == len(b) {
+		return a < b
+	}
+	return len(a) < len(b)
+}
+
+func lessName(a, b string) bool {
+	return strings.ToLower(a) < strings.ToLower(b)
+}
+
+// GetAllSorted returns all the cached sections in the given order.
+func (c *SectionClient) GetAllSorted(by SortKey) []Section {
+	res := c.GetAll()
+	sort.SliceStable(res, func(i, j int) bool {
+		switch by {
+		case SortByName:
+			return lessName(res[i].Name, res[j].Name)
+		case SortByOrder:
+			return res[i].SectionOrder < res[j].SectionOrder
+		default:
+			return lessID(res[i].ID, res[j].ID)
+		}
+	})
+	return res
+}
+
+// GetAllSorted returns all the cached projects in the given order.
+func (c *ProjectClient) GetAllSorted(by SortKey) []Project {
+	res := c.GetAll()
+	sort.SliceStable(res, func(i, j int) bool {
+		switch by {
+		case SortByName:
+			return lessName(res[i].Name, res[j].Name)
+		case SortByOrder:
+			return res[i].ChildOrder < res[j].ChildOrder
+		default:
+			return lessID(res[i].ID, res[j].ID)
+		}
+	})
+	return res
+}
+
+// GetAllSorted returns all the cached items in the given order.
+func (c *ItemClient) GetAllSorted(by SortKey) []Item {
+	res := c.GetAll()
+	sort.SliceStable(res, func(i, j int) bool {
+		switch by {
+		case SortByName:
+			return lessName(res[i].Content, res[j].Content)
+		case SortByOrder:
+			return res[i].ChildOrder < res[j].ChildOrder
+		default:
+			return lessID(res[i].ID, res[j].ID)
+		}
+	})
+	return res
+}
@@ -0,0 +1,44 @@
+package todoist
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSectionClient_GetAllSorted(t *testing.T) {
+	client, teardown := newTestClient(t, nil)
+	defer teardown()
+	for _, s := range []struct {
+		id    ID
+		name  string
+		order int
+	}{
+		{"10", "beta", 1},
+		{"9", "Alpha", 3},
+		{"100", "gamma", 2},
+	} {
+		section := Section{Name: s.name, SectionOrder: s.order}
+		section.ID = s.id
+		client.Section.cache.store(section)
+	}
+	ids := func(sections []Section) []ID {
+		var res []ID
+		for _, s := range sections {
+			res = append(res, s.ID)
+		}
+		return res
+	}
+
+	for by, expect := range map[SortKey][]ID{
+		SortByName:  {"9", "10", "100"},
+		SortByOrder: {"10", "100", "9"},
+		SortByID:    {"9", "10", "100"},
+	} {
+		if actual := ids(client.Section.GetAllSorted(by)); !reflect.DeepEqual(actual, expect) {
+			t.Errorf("Expect %v, but got %v", expect, actual)
+		}
+	}
+	if actual := ids(client.Section.GetAll()); !reflect.DeepEqual(actual, []ID{"10", "9", "100"}) {
+		t.Errorf("Expect cache order to be kept, but got %v", actual)
+	}
+}
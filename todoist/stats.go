@@ -0,0 +1,108 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Stats is the productivity stats of the user.
+type Stats struct {
+	KarmaLastUpdate    float64             `json:"karma_last_update"`
+	KarmaTrend         string              `json:"karma_trend"`
+	DaysItems          []StatsDayItems     `json:"days_items"`
+	CompletedCount     int                 `json:"completed_count"`
+	KarmaUpdateReasons []KarmaUpdateReason `json:"karma_update_reasons"`
+	Karma              float64             `json:"karma"`
+	WeekItems          []StatsWeekItems    `json:"week_items"`
+	KarmaGraph         string              `json:"karma_graph"`
+	Goals              Goals               `json:"goals"`
+}
+
+// StatsProjectItems is the number of completed items in the project.
+type StatsProjectItems struct {
+	Completed int `json:"completed"`
+	ID        ID  `json:"id"`
+}
+
+// StatsDayItems is the completed items of the day, e.g. Date is "2020-01-02".
+type StatsDayItems struct {
+	Date           string              `json:"date"`
+	Items          []StatsProjectItems `json:"items"`
+	TotalCompleted int                 `json:"total_completed"`
+}
+
+// StatsWeekItems is the completed items of the week, e.g. From is "2020-01-06" and To is "2020-01-12".
+type StatsWeekItems struct {
+	From           string              `json:"from"`
+	To             string              `json:"to"`
+	Items          []StatsProjectItems `json:"items"`
+	TotalCompleted int                 `json:"total_completed"`
+}
+
+type KarmaUpdateReason struct {
+	PositiveKarmaReasons []int   `json:"positive_karma_reasons"`
+	NewKarma             float64 `json:"new_karma"`
+	NegativeKarma        float64 `json:"negative_karma"`
+	PositiveKarma        float64 `json:"positive_karma"`
+	NegativeKarmaReasons []int   `json:"negative_karma_reasons"`
+	Time                 string  `json:"time"`
+}
+
+type Streak struct {
+	Count int    `json:"count"`
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+type Goals struct {
+	KarmaDisabled       int    `json:"karma_disabled"`
+	UserID              ID     `json:"user_id"`
+	LastDailyStreak     Streak `json:"last_daily_streak"`
+	VacationMode        int    `json:"vacation_mode"`
+	IgnoreDays          []int  `json:"ignore_days"`
+	MaxWeeklyStreak     Streak `json:"max_weekly_streak"`
+	CurrentWeeklyStreak Streak `json:"current_weekly_streak"`
+	CurrentDailyStreak  Streak `json:"current_daily_streak"`
+	LastWeeklyStreak    Streak `json:"last_weekly_streak"`
+	WeeklyGoal          int    `json:"weekly_goal"`
+	MaxDailyStreak      Streak `json:"max_daily_streak"`
+	DailyGoal           int    `json:"daily_goal"`
+}
+
+// CompletedOn returns the number of items completed on the date of t in its location.
+// It returns 0 if the date is out of the daily breakdown.
+func (s Stats) CompletedOn(t time.Time) int {
+	date := t.Format(dateLayout)
+	for _, day := range s.DaysItems {
+		if day.Date == date {
+			return day.TotalCompleted
+		}
+	}
+	return 0
+}
+
+// StatsClient encapsulate client operations for the productivity stats.
+type StatsClient struct {
+	*Client
+}
+
+func (c *StatsClient) Get(ctx context.Context) (*Stats, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, pathCompletedGetStats, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	if (res.StatusCode / 100) != 2 {
+		return nil, newAPIError(res)
+	}
+	var out Stats
+	if err = c.decodeBody(res, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
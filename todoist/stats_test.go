@@ -0,0 +1,46 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStatsClient_Get(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/completed/get_stats" {
+			t.Errorf("Expect %s, but got %s", "/completed/get_stats", r.URL.Path)
+		}
+		w.Write([]byte(`{
+			"karma": 1234.5, "karma_trend": "up", "completed_count": 42,
+			"days_items": [
+				{"date": "2020-01-02", "total_completed": 3, "items": [{"id": 10, "completed": 3}]},
+				{"date": "2020-01-01", "total_completed": 1, "items": [{"id": 10, "completed": 1}]}
+			],
+			"week_items": [{"from": "2019-12-30", "to": "2020-01-05", "total_completed": 4, "items": []}],
+			"goals": {"daily_goal": 5, "weekly_goal": 25, "current_daily_streak": {"count": 2, "start": "2020-01-01", "end": "2020-01-02"}}
+		}`))
+	})
+	defer teardown()
+
+	stats, err := client.Stats.Get(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if stats.Karma != 1234.5 || stats.Goals.DailyGoal != 5 || stats.Goals.CurrentDailyStreak.Count != 2 {
+		t.Errorf("Unexpect stats: %v", stats)
+	}
+	if len(stats.WeekItems) != 1 || stats.WeekItems[0].TotalCompleted != 4 {
+		t.Errorf("Unexpect week items: %v", stats.WeekItems)
+	}
+	if n := stats.CompletedOn(time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)); n != 3 {
+		t.Errorf("Expect %d, but got %d", 3, n)
+	}
+	if n := stats.CompletedOn(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)); n != 0 {
+		t.Errorf("Expect %d, but got %d", 0, n)
+	}
+	if len(client.queue) != 0 {
+		t.Errorf("Expect no command, but got %d", len(client.queue))
+	}
+}
@@ -1,24 +1,33 @@
 package todoist
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
 type SyncState struct {
-	SyncToken string `json:"sync_token"`
-	FullSync  bool   `json:"full_sync"`
-	// User User `json:"user"`
+	SyncToken    string    `json:"sync_token"`
+	FullSync     bool      `json:"full_sync"`
+	User         *User     `json:"user,omitempty"`
 	Projects     []Project `json:"projects"`
 	ProjectNotes []Note    `json:"project_notes"`
+	Sections     []Section `json:"sections"`
 	Items        []Item    `json:"items"`
 	Notes        []Note    `json:"notes"`
 	Labels       []Label   `json:"labels"`
 	Filters      []Filter  `json:"filters"`
 	// DayOrders struct {} `json:"day_orders"`
-	// DayOrdersTimestamp string `json:"day_orders_timestamp"`
-	Reminders []Reminder `json:"reminders"`
-	// Collaborators []interface{} `json:"collaborators"`
-	// CollaboratorStates []CollaboratorState `json:"collaborator_states"`
+	DayOrdersTimestamp string              `json:"day_orders_timestamp,omitempty"`
+	Reminders          []Reminder          `json:"reminders"`
+	Collaborators      []Collaborator      `json:"collaborators"`
+	CollaboratorStates []CollaboratorState `json:"collaborator_states"`
 	// LiveNotifications []LiveNotification `json:"live_notifications"`
 	// LiveNotificationsLastReadID int `json:"live_notifications_last_read_id"`
 	// Locations []interface{} `json:"locations"`
-	// TempIDMapping struct {} `json:"temp_id_mapping"`
+	SyncStatus    map[UUID]CommandStatus `json:"sync_status,omitempty"`
+	TempIDMapping map[ID]ID              `json:"temp_id_mapping,omitempty"`
 }
 
 type Command struct {
@@ -27,3 +36,189 @@ type Command struct {
 	UUID   UUID        `json:"uuid"`
 	TempID ID          `json:"temp_id"`
 }
+
+// CommandStatus is a result of the command reported in sync_status.
+// The server returns "ok" for a succeeded command, or an error object.
+type CommandStatus struct {
+	Error *CommandError
+}
+
+func (s CommandStatus) IsOK() bool {
+	return s.Error == nil
+}
+
+func (s CommandStatus) MarshalJSON() ([]byte, error) {
+	if s.IsOK() {
+		return []byte(`"ok"`), nil
+	}
+	return json.Marshal(s.Error)
+}
+
+func (s *CommandStatus) UnmarshalJSON(b []byte) error {
+	if string(b) == `"ok"` {
+		s.Error = nil
+		return nil
+	}
+	var e CommandError
+	if err := json.Unmarshal(b, &e); err != nil {
+		return err
+	}
+	s.Error = &e
+	return nil
+}
+
+// Sentinel errors matched by CommandError with the error tag, e.g. errors.Is(err, ErrLimitsReached).
+var (
+	ErrLimitsReached   = errors.New("limits reached")
+	ErrInvalidTempID   = errors.New("invalid temporary id")
+	ErrItemNotFound    = errors.New("item not found")
+	ErrProjectNotFound = errors.New("project not found")
+	ErrSectionNotFound = errors.New("section not found")
+)
+
+// dayOrdersConflictTag is the error tag of item_update_day_orders with a stale day_orders_timestamp.
+const dayOrdersConflictTag = "DAY_ORDERS_CONFLICT"
+
+var commandErrorTags = map[string]error{
+	"LIMITS_REACHED":    ErrLimitsReached,
+	"INVALID_TEMPID":    ErrInvalidTempID,
+	"ITEM_NOT_FOUND":    ErrItemNotFound,
+	"PROJECT_NOT_FOUND": ErrProjectNotFound,
+	"SECTION_NOT_FOUND": ErrSectionNotFound,
+}
+
+// NotFoundError is returned when the requested object does not exist.
+// errors.Is matches it with the sentinel error of the resource, e.g. ErrItemNotFound.
+type NotFoundError struct {
+	Resource string
+	ID       ID
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s %s not found", e.Resource, e.ID)
+}
+
+// Is reports whether the resource corresponds to the sentinel error.
+func (e *NotFoundError) Is(target error) bool {
+	switch e.Resource {
+	case "item":
+		return target == ErrItemNotFound
+	case "project":
+		return target == ErrProjectNotFound
+	case "section":
+		return target == ErrSectionNotFound
+	}
+	return false
+}
+
+// ConflictError is returned when the object changed since it was read.
+type ConflictError struct {
+	Resource string
+	ID       ID
+}
+
+func (e *ConflictError) Error() string {
+	if e.ID.IsZero() {
+		return fmt.Sprintf("%s changed since it was read", e.Resource)
+	}
+	return fmt.Sprintf("%s %s changed since it was read", e.Resource, e.ID)
+}
+
+// CommandError represents a command rejected by the server.
+type CommandError struct {
+	UUID        UUID   `json:"-"`
+	CommandType string `json:"-"`
+	Code        int    `json:"error_code"`
+	Tag         string `json:"error_tag,omitempty"`
+	Message     string `json:"error"`
+}
+
+func (e CommandError) Error() string {
+	return fmt.Sprintf("command %s %s failed: %s (error code: %d)", e.CommandType, e.UUID, e.Message, e.Code)
+}
+
+// Is reports whether the error tag corresponds to the sentinel error.
+func (e CommandError) Is(target error) bool {
+	sentinel, ok := commandErrorTags[e.Tag]
+	return ok && sentinel == target
+}
+
+// CommitError is returned when some of the committed commands are rejected.
+// Errors is ordered as the commands were queued.
+// errors.Is and errors.As look into each CommandError, and errors.As finds the first one.
+// errors.As finds *ConflictError of "day_orders" if day orders are rejected for a stale timestamp.
+type CommitError struct {
+	Errors []CommandError
+}
+
+func (e *CommitError) Error() string {
+	var arr []string
+	for _, err := range e.Errors {
+		arr = append(arr, err.Error())
+	}
+	return fmt.Sprintf("%d command(s) failed: %s", len(e.Errors), strings.Join(arr, ", "))
+}
+
+func (e *CommitError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if err.Is(target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *CommitError) As(target interface{}) bool {
+	if len(e.Errors) == 0 {
+		return false
+	}
+	switch t := target.(type) {
+	case *CommandError:
+		*t = e.Errors[0]
+		return true
+	case **CommandError:
+		err := e.Errors[0]
+		*t = &err
+		return true
+	case **ConflictError:
+		for _, err := range e.Errors {
+			if err.Tag == dayOrdersConflictTag {
+				*t = &ConflictError{Resource: "day_orders"}
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func newCommitError(commands []Command, status map[UUID]CommandStatus) error {
+	var errs []CommandError
+	for _, command := range commands {
+		if s, ok := status[command.UUID]; ok && !s.IsOK() {
+			e := *s.Error
+			e.UUID = command.UUID
+			e.CommandType = command.Type
+			errs = append(errs, e)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return &CommitError{Errors: errs}
+}
+
+// ChunkError is returned by Commit when a chunk of commands fails.
+// Commands holds the commands sent in the chunk, so that the chunk can be retried.
+type ChunkError struct {
+	Index    int
+	Commands []Command
+	Err      error
+}
+
+func (e *ChunkError) Error() string {
+	return fmt.Sprintf("failed to commit chunk %d (%d command(s)): %s", e.Index, len(e.Commands), e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
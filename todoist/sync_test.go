@@ -0,0 +1,49 @@
+package todoist
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCommitError_Errors(t *testing.T) {
+	var status map[UUID]CommandStatus
+	b := []byte(`{
+		"a": "ok",
+		"b": {"error_code": 35, "error_tag": "LIMITS_REACHED", "error": "Maximum number of sections per project reached"},
+		"c": {"error_code": 20, "error_tag": "SECTION_NOT_FOUND", "error": "Section not found"}
+	}`)
+	if err := json.Unmarshal(b, &status); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	commands := []Command{
+		{Type: "section_add", UUID: "a"},
+		{Type: "section_add", UUID: "b"},
+		{Type: "section_update", UUID: "c"},
+	}
+	err := error(&ChunkError{Err: newCommitError(commands, status)})
+
+	var commandErr CommandError
+	if !errors.As(err, &commandErr) {
+		t.Fatalf("Expect CommandError, but got %v", err)
+	}
+	if commandErr.UUID != "b" || commandErr.CommandType != "section_add" || commandErr.Code != 35 {
+		t.Errorf("Unexpect command error: %v", commandErr)
+	}
+	if !errors.Is(err, ErrLimitsReached) || !errors.Is(err, ErrSectionNotFound) {
+		t.Errorf("Expect sentinel errors to match, but got %v", err)
+	}
+	if errors.Is(err, ErrItemNotFound) {
+		t.Errorf("Expect %s not to match", ErrItemNotFound)
+	}
+	var commitErr *CommitError
+	if errors.As(err, &commitErr) {
+		for _, e := range commitErr.Errors {
+			if e.UUID == "c" && !errors.Is(e, ErrSectionNotFound) {
+				t.Errorf("Expect %s, but got %v", ErrSectionNotFound, e)
+			}
+		}
+	} else {
+		t.Errorf("Expect *CommitError, but got %v", err)
+	}
+}
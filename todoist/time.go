@@ -1,6 +1,9 @@
 package todoist
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"github.com/fatih/color"
 	"strconv"
 	"time"
@@ -27,6 +30,172 @@ type Due struct {
 	String      string `json:"string"`
 	Lang        string `json:"lang"`
 	IsRecurring bool   `json:"is_recurring"`
+	// layout is the format of the date, which is kept to send back it as is.
+	layout string
+}
+
+// NewFullDayDue returns an all-day due on the date of t.
+func NewFullDayDue(t time.Time) Due {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	return Due{Date: Time{date}, layout: dateLayout}
+}
+
+// NewFloatingDue returns a due at t in the user's timezone, whichever it is.
+func NewFloatingDue(t time.Time) Due {
+	return Due{Date: Time{t}, layout: datetimeLayout}
+}
+
+// NewZonedDue returns a due fixed at t in the given IANA timezone, e.g. "Europe/Madrid".
+func NewZonedDue(t time.Time, timezone string) (Due, error) {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return Due{}, err
+	}
+	return Due{Date: Time{t.In(loc)}, Timezone: timezone, layout: datetimeTzLayout}, nil
+}
+
+// IsFullDay reports whether the due is an all-day date without time.
+func (d Due) IsFullDay() bool {
+	if len(d.layout) == 0 {
+		return !d.Date.IsZero() && d.Date.IsFullDay()
+	}
+	return d.layout == dateLayout
+}
+
+// IsFloating reports whether the due has a time without timezone,
+// which is interpreted in the user's current timezone.
+func (d Due) IsFloating() bool {
+	return d.layout == datetimeLayout
+}
+
+// Deadline is the date by which the item must be done, apart from the due date.
+// It has no time, recurrence nor timezone.
+type Deadline struct {
+	Date Time   `json:"date"`
+	Lang string `json:"lang,omitempty"`
+}
+
+// NewDeadline returns a deadline on the date of t.
+func NewDeadline(t time.Time) Deadline {
+	return Deadline{Date: Time{time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)}}
+}
+
+type rawDeadline struct {
+	Date string `json:"date"`
+	Lang string `json:"lang,omitempty"`
+}
+
+func (d Deadline) MarshalJSON() ([]byte, error) {
+	return json.Marshal(rawDeadline{Date: d.Date.Time.Format(dateLayout), Lang: d.Lang})
+}
+
+func (d *Deadline) UnmarshalJSON(b []byte) error {
+	var raw rawDeadline
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	t, err := time.ParseInLocation(dateLayout, raw.Date, time.Local)
+	if err != nil {
+		return err
+	}
+	*d = Deadline{Date: Time{t}, Lang: raw.Lang}
+	return nil
+}
+
+const (
+	DurationUnitMinute = "minute"
+	DurationUnitDay    = "day"
+)
+
+// Duration is the time the item takes from the due, in minutes or days.
+type Duration struct {
+	Amount int    `json:"amount"`
+	Unit   string `json:"unit"`
+}
+
+// NewDuration returns a duration of the amount in the unit, DurationUnitMinute or DurationUnitDay.
+func NewDuration(amount int, unit string) (*Duration, error) {
+	if amount <= 0 {
+		return nil, errors.New("new duration requires a positive amount")
+	}
+	if unit != DurationUnitMinute && unit != DurationUnitDay {
+		return nil, fmt.Errorf("invalid duration unit: %s", unit)
+	}
+	return &Duration{Amount: amount, Unit: unit}, nil
+}
+
+// Minutes returns the duration in minutes. A day is 24 hours.
+func (d Duration) Minutes() int {
+	if d.Unit == DurationUnitDay {
+		return d.Amount * 24 * 60
+	}
+	return d.Amount
+}
+
+type rawDue struct {
+	Date        *string `json:"date"`
+	Timezone    *string `json:"timezone"`
+	String      string  `json:"string"`
+	Lang        string  `json:"lang,omitempty"`
+	IsRecurring bool    `json:"is_recurring"`
+}
+
+func (d Due) MarshalJSON() ([]byte, error) {
+	raw := rawDue{String: d.String, Lang: d.Lang, IsRecurring: d.IsRecurring}
+	if len(d.Timezone) != 0 {
+		raw.Timezone = &d.Timezone
+	}
+	if !d.Date.IsZero() {
+		var s string
+		switch d.layout {
+		case dateLayout, datetimeLayout:
+			s = d.Date.Time.Format(d.layout)
+		case datetimeTzLayout:
+			s = d.Date.Time.UTC().Format(datetimeTzLayout)
+		default:
+			b, err := d.Date.MarshalJSON()
+			if err != nil {
+				return nil, err
+			}
+			if s, err = strconv.Unquote(string(b)); err != nil {
+				return nil, err
+			}
+		}
+		raw.Date = &s
+	}
+	return json.Marshal(raw)
+}
+
+func (d *Due) UnmarshalJSON(b []byte) error {
+	var raw rawDue
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*d = Due{String: raw.String, Lang: raw.Lang, IsRecurring: raw.IsRecurring}
+	if raw.Timezone != nil {
+		d.Timezone = *raw.Timezone
+	}
+	if raw.Date == nil || len(*raw.Date) == 0 {
+		return nil
+	}
+	for _, layout := range []string{dateLayout, datetimeLayout} {
+		// FIXME: refer to user.tz_info.timezone
+		if t, err := time.ParseInLocation(layout, *raw.Date, time.Local); err == nil {
+			d.Date = Time{t}
+			d.layout = layout
+			return nil
+		}
+	}
+	t, err := time.Parse(datetimeTzLayout, *raw.Date)
+	if err != nil {
+		return err
+	}
+	if loc, err := time.LoadLocation(d.Timezone); err == nil && len(d.Timezone) != 0 {
+		t = t.In(loc)
+	}
+	d.Date = Time{t}
+	d.layout = datetimeTzLayout
+	return nil
 }
 
 type Time struct {
@@ -93,3 +93,53 @@ func TestTimeJson(t *testing.T) {
 		}
 	}
 }
+
+func TestDueJson(t *testing.T) {
+	tests := []struct {
+		s        string
+		fullDay  bool
+		floating bool
+	}{
+		{`{"date":"2014-09-26","timezone":null,"string":"every day","lang":"en","is_recurring":true}`, true, false},
+		{`{"date":"2014-09-26T00:00:00","timezone":null,"string":"","is_recurring":false}`, false, true},
+		{`{"date":"2014-09-26T08:25:05Z","timezone":"Europe/Madrid","string":"","is_recurring":false}`, false, false},
+	}
+	for _, test := range tests {
+		var due Due
+		if err := json.Unmarshal([]byte(test.s), &due); err != nil {
+			t.Fatalf("Unexpect error: %s", err)
+		}
+		if due.IsFullDay() != test.fullDay || due.IsFloating() != test.floating {
+			t.Errorf("%s: unexpected kind, full day: %v, floating: %v", test.s, due.IsFullDay(), due.IsFloating())
+		}
+		b, err := json.Marshal(due)
+		if err != nil || string(b) != test.s {
+			t.Errorf("mismatch:\n exp=%s\n got=%s\n\n", test.s, string(b))
+		}
+	}
+
+	var due Due
+	json.Unmarshal([]byte(`{"date":"2014-09-26T08:25:05Z","timezone":"Asia/Tokyo","string":"","is_recurring":false}`), &due)
+	if due.Date.Hour() != 17 || due.Date.Location().String() != "Asia/Tokyo" {
+		t.Errorf("Expect the date in the timezone, but got %s", due.Date.Time)
+	}
+}
+
+func TestItem_IsOverdue(t *testing.T) {
+	now := time.Date(2014, 9, 26, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		due Due
+		v   bool
+	}{
+		{Due{}, false},
+		{NewFullDayDue(now), false},
+		{NewFullDayDue(now.AddDate(0, 0, -1)), true},
+		{NewFloatingDue(now.Add(-time.Minute)), true},
+		{NewFloatingDue(now.Add(time.Minute)), false},
+	}
+	for _, test := range tests {
+		if v := (Item{Due: test.due}).IsOverdue(now); v != test.v {
+			t.Errorf("%v: expect %v, but got %v", test.due, test.v, v)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Package todoisttest provides a fake sync API to test code using the todoist package.
+//
+// MockServer records the commands of syncs and replies the programmed responses,
+// so that tests can assert the commands sent by Commit without HTTP boilerplate:
+//
+//	server := todoisttest.NewServer()
+//	defer server.Close()
+//	client, err := server.NewClient()
+//	...
+//	client.Section.Add(*section)
+//	client.Commit(ctx)
+//	commands := server.CommandsOf("section_add")
+package todoisttest
+
+import (
+	"encoding/json"
+	"github.com/kobtea/go-todoist/todoist"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// firstMockID is the first id assigned to temporary ids by MockServer.
+const firstMockID = 1000000
+
+// MockServer is a fake of the sync endpoint.
+//
+// A sync is replied by the response pushed by PushResponse if any, or by the state set by SetState
+// for full syncs, or by an empty response otherwise. Every reply reports "ok" for the commands
+// and maps their temporary ids to new ids, unless the response has sync_status or temp_id_mapping.
+// projects/get_data is replied with the project, its sections, items and notes in the state set by SetState,
+// e.g. for Commit unarchiving sections. Requests to the other endpoints are replied with 404 Not Found.
+type MockServer struct {
+	// URL is the base URL of the server for todoist.WithBaseURL.
+	URL string
+
+	server    *httptest.Server
+	cacheDir  string
+	commands  []todoist.Command
+	responses []todoist.SyncState
+	state     todoist.SyncState
+	syncs     int
+	nextID    int
+	mu        sync.Mutex
+}
+
+// NewServer starts a MockServer. Close it at the end of the test.
+func NewServer() *MockServer {
+	s := &MockServer{nextID: firstMockID}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL
+	return s
+}
+
+// Close shuts down the server and removes the cache directories of the clients by NewClient.
+func (s *MockServer) Close() {
+	s.server.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cacheDir) != 0 {
+		os.RemoveAll(s.cacheDir)
+	}
+}
+
+// NewClient returns a client of the server with a temporary cache directory and without retries.
+// The options are applied after them, e.g. todoist.WithSyncToken.
+func (s *MockServer) NewClient(opts ...todoist.Option) (*todoist.Client, error) {
+	s.mu.Lock()
+	if len(s.cacheDir) == 0 {
+		dir, err := ioutil.TempDir("", "go-todoist-test")
+		if err != nil {
+			s.mu.Unlock()
+			return nil, err
+		}
+		s.cacheDir = dir
+	}
+	dir, err := ioutil.TempDir(s.cacheDir, "client")
+	s.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]todoist.Option{todoist.WithBaseURL(s.URL), todoist.WithCacheDir(dir)}, opts...)
+	client, err := todoist.NewClient("mock-token", opts...)
+	if err != nil {
+		return nil, err
+	}
+	client.RetryPolicy = nil
+	return client, nil
+}
+
+// SetState sets the resources replied to full syncs, i.e. syncs with the sync token "*".
+func (s *MockServer) SetState(state todoist.SyncState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+}
+
+// PushResponse programs the reply to the next sync. Responses are replied in the pushed order.
+func (s *MockServer) PushResponse(state todoist.SyncState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.responses = append(s.responses, state)
+}
+
+// Commands returns the commands received so far, in the received order.
+// Args of the commands are decoded as JSON objects, i.e. map[string]interface{}.
+func (s *MockServer) Commands() []todoist.Command {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make([]todoist.Command, len(s.commands))
+	copy(res, s.commands)
+	return res
+}
+
+// CommandsOf returns the commands of the type received so far, e.g. "section_add".
+func (s *MockServer) CommandsOf(commandType string) []todoist.Command {
+	var res []todoist.Command
+	for _, command := range s.Commands() {
+		if command.Type == commandType {
+			res = append(res, command)
+		}
+	}
+	return res
+}
+
+// Syncs returns the number of syncs received so far, including the ones without commands.
+func (s *MockServer) Syncs() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.syncs
+}
+
+func (s *MockServer) handle(w http.ResponseWriter, r *http.Request) {
+	if strings.HasSuffix(r.URL.Path, "/projects/get_data") {
+		s.handleProjectData(w, r)
+		return
+	}
+	if path.Base(r.URL.Path) != "sync" {
+		http.NotFound(w, r)
+		return
+	}
+	var commands []todoist.Command
+	if v := r.FormValue("commands"); len(v) != 0 {
+		if err := json.Unmarshal([]byte(v), &commands); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	s.syncs++
+	s.commands = append(s.commands, commands...)
+	var res todoist.SyncState
+	switch {
+	case len(s.responses) != 0:
+		res = s.responses[0]
+		s.responses = s.responses[1:]
+	case r.FormValue("sync_token") == "*":
+		res = s.state
+		res.FullSync = true
+	}
+	if len(res.SyncToken) == 0 {
+		res.SyncToken = "mock-sync-token-" + strconv.Itoa(s.syncs)
+	}
+	if res.SyncStatus == nil && len(commands) != 0 {
+		res.SyncStatus = map[todoist.UUID]todoist.CommandStatus{}
+		for _, command := range commands {
+			res.SyncStatus[command.UUID] = todoist.CommandStatus{}
+		}
+	}
+	if res.TempIDMapping == nil {
+		for _, command := range commands {
+			if command.TempID.IsZero() {
+				continue
+			}
+			if res.TempIDMapping == nil {
+				res.TempIDMapping = map[todoist.ID]todoist.ID{}
+			}
+			res.TempIDMapping[command.TempID] = todoist.ID(strconv.Itoa(s.nextID))
+			s.nextID++
+		}
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
+
+func (s *MockServer) handleProjectData(w http.ResponseWriter, r *http.Request) {
+	id := todoist.ID(r.FormValue("project_id"))
+	s.mu.Lock()
+	var res todoist.ProjectData
+	for _, project := range s.state.Projects {
+		if project.ID == id {
+			res.Project = project
+		}
+	}
+	for _, section := range s.state.Sections {
+		if section.ProjectID == id {
+			res.Sections = append(res.Sections, section)
+		}
+	}
+	for _, item := range s.state.Items {
+		if item.ProjectID == id {
+			res.Items = append(res.Items, item)
+		}
+	}
+	for _, note := range s.state.ProjectNotes {
+		if note.ProjectID == id {
+			res.Notes = append(res.Notes, note)
+		}
+	}
+	s.mu.Unlock()
+
+	if res.Project.ID.IsZero() {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(res)
+}
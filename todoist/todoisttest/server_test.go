@@ -0,0 +1,103 @@
+package todoisttest
+
+import (
+	"context"
+	"errors"
+	"github.com/kobtea/go-todoist/todoist"
+	"testing"
+)
+
+func TestMockServer_Commit(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	project := todoist.Project{Name: "Project"}
+	project.ID = "1"
+	server.SetState(todoist.SyncState{Projects: []todoist.Project{project}})
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err = client.FullSync(context.Background(), []todoist.Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if p := client.Project.Resolve("1"); p == nil || p.Name != "Project" {
+		t.Fatalf("Unexpect project: %v", p)
+	}
+
+	section, err := todoist.NewSection("Section", &todoist.NewSectionOpts{ProjectID: "1"})
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if _, err = client.Section.Add(*section); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err = client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	commands := server.CommandsOf("section_add")
+	if len(commands) != 1 || len(server.Commands()) != 1 {
+		t.Fatalf("Expect 1 section_add, but got %v", server.Commands())
+	}
+	if args := commands[0].Args.(map[string]interface{}); args["name"] != "Section" {
+		t.Errorf("Expect %s, but got %v", "Section", args["name"])
+	}
+	if id := client.ResolveTempID(section.ID); id != "1000000" {
+		t.Errorf("Expect %s, but got %s", "1000000", id)
+	}
+	if server.Syncs() != 2 {
+		t.Errorf("Expect %d, but got %d", 2, server.Syncs())
+	}
+}
+
+func TestMockServer_PushResponse(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client, err := server.NewClient(todoist.WithSyncToken("token"))
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err = client.Project.Archive("1"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	uuid := client.Pending()[0].UUID
+	server.PushResponse(todoist.SyncState{
+		SyncStatus: map[todoist.UUID]todoist.CommandStatus{
+			uuid: {Error: &todoist.CommandError{Code: 35, Tag: "LIMITS_REACHED", Message: "limits reached"}},
+		},
+	})
+	err = client.Commit(context.Background())
+	if !errors.Is(err, todoist.ErrLimitsReached) {
+		t.Errorf("Expect %s, but got %v", todoist.ErrLimitsReached, err)
+	}
+}
+
+func TestMockServer_UnarchiveSection(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	project := todoist.Project{Name: "Project"}
+	project.ID = "1"
+	section := todoist.Section{Name: "Section", ProjectID: "1"}
+	section.ID = "2"
+	item := todoist.Item{Content: "Item", ProjectID: "1", SectionID: "2"}
+	item.ID = "3"
+	server.SetState(todoist.SyncState{Projects: []todoist.Project{project}, Sections: []todoist.Section{section}})
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err = client.FullSync(context.Background(), []todoist.Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	server.SetState(todoist.SyncState{Projects: []todoist.Project{project}, Sections: []todoist.Section{section}, Items: []todoist.Item{item}})
+	if err = client.Section.Unarchive("2"); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if err = client.Commit(context.Background()); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if i := client.Item.Resolve("3"); i == nil || i.Content != "Item" {
+		t.Errorf("Expect the item of the section, but got %v", i)
+	}
+}
@@ -0,0 +1,47 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RevokeTokenError is returned when the server rejects the revocation of the access token.
+type RevokeTokenError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *RevokeTokenError) Error() string {
+	return fmt.Sprintf("failed to revoke access token, status code: %d, body: %s", e.StatusCode, e.Body)
+}
+
+// RevokeToken revokes the access token issued to the app by the OAuth flow, e.g. when the user uninstalls the app.
+// The token of the client is neither sent nor modified, so that any client can revoke tokens of the users.
+func (c *Client) RevokeToken(ctx context.Context, clientID, clientSecret, accessToken string) error {
+	u := c.resolvePath(pathAccessTokensRevoke)
+	values := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"access_token":  {accessToken},
+	}
+	req, err := http.NewRequest(http.MethodPost, u.String(), strings.NewReader(values.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", c.userAgent)
+	res, err := c.do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if (res.StatusCode / 100) != 2 {
+		body, _ := ioutil.ReadAll(res.Body)
+		return &RevokeTokenError{StatusCode: res.StatusCode, Body: string(body)}
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+package todoist
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestClient_RevokeToken(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/access_tokens/revoke" {
+			t.Errorf("Expect %s, but got %s", "/access_tokens/revoke", r.URL.Path)
+		}
+		if token := r.FormValue("token"); len(token) != 0 {
+			t.Errorf("Expect token not to be sent, but got %s", token)
+		}
+		if r.FormValue("client_id") != "id" || r.FormValue("client_secret") != "secret" {
+			t.Errorf("Unexpect form: %v", r.Form)
+		}
+		if r.FormValue("access_token") != "user-token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"error": "invalid token"}`))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer teardown()
+
+	if err := client.RevokeToken(context.Background(), "id", "secret", "user-token"); err != nil {
+		t.Errorf("Unexpect error: %s", err)
+	}
+	err := client.RevokeToken(context.Background(), "id", "secret", "unknown")
+	var e *RevokeTokenError
+	if !errors.As(err, &e) || e.StatusCode != http.StatusForbidden {
+		t.Errorf("Expect revoke token error, but got %v", err)
+	}
+	if client.Token != "test-token" {
+		t.Errorf("Expect %s, but got %s", "test-token", client.Token)
+	}
+}
@@ -0,0 +1,95 @@
+package todoist
+
+import (
+	"sort"
+)
+
+// ProjectTree is the sections and the items of a project as nested in the official apps.
+type ProjectTree struct {
+	Project Project
+	// Items are the root items without section, or with a section not cached.
+	Items    []ItemTree
+	Sections []SectionTree
+}
+
+// SectionTree is a section with its root items.
+type SectionTree struct {
+	Section Section
+	Items   []ItemTree
+}
+
+// ItemTree is an item with its subtasks.
+type ItemTree struct {
+	Item     Item
+	Subtasks []ItemTree
+}
+
+// Tree returns the cached sections and items of the project, ordered by section order and child order.
+// Items are nested under their parents. An item whose parent is not in the project, or is in a malformed
+// cycle of parents, is a root item, so that every item appears once.
+func (c *ProjectClient) Tree(projectID ID) ProjectTree {
+	var res ProjectTree
+	if project := c.Resolve(projectID); project != nil {
+		res.Project = *project
+	} else {
+		res.Project.ID = projectID
+	}
+
+	var items []Item
+	inProject := map[ID]bool{}
+	for _, item := range c.Item.GetAll() {
+		if item.ProjectID == projectID {
+			items = append(items, item)
+			inProject[item.ID] = true
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].ChildOrder < items[j].ChildOrder
+	})
+	children := map[ID][]Item{}
+	var roots []Item
+	for _, item := range items {
+		if !item.ParentID.IsZero() && inProject[item.ParentID] {
+			children[item.ParentID] = append(children[item.ParentID], item)
+		} else {
+			roots = append(roots, item)
+		}
+	}
+
+	seen := map[ID]bool{}
+	var build func(item Item) ItemTree
+	build = func(item Item) ItemTree {
+		seen[item.ID] = true
+		node := ItemTree{Item: item}
+		for _, child := range children[item.ID] {
+			if !seen[child.ID] {
+				node.Subtasks = append(node.Subtasks, build(child))
+			}
+		}
+		return node
+	}
+	bySection := map[ID][]ItemTree{}
+	for _, item := range roots {
+		bySection[item.SectionID] = append(bySection[item.SectionID], build(item))
+	}
+	for _, item := range items {
+		if !seen[item.ID] {
+			bySection[item.SectionID] = append(bySection[item.SectionID], build(item))
+		}
+	}
+
+	for _, section := range c.Section.GetByProject(projectID) {
+		res.Sections = append(res.Sections, SectionTree{Section: section, Items: bySection[section.ID]})
+		delete(bySection, section.ID)
+	}
+	res.Items = bySection[""]
+	delete(bySection, "")
+	// items in sections not cached, in the order of the items.
+	for _, item := range items {
+		if trees, ok := bySection[item.SectionID]; ok {
+			res.Items = append(res.Items, trees...)
+			delete(bySection, item.SectionID)
+		}
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+package todoist
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestProjectClient_Tree(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpect request: %s", r.URL.Path)
+	})
+	defer teardown()
+
+	client.Project.cache.store(Project{Entity: Entity{ID: "1"}, Name: "Work"})
+	client.Section.cache.store(Section{Entity: Entity{ID: "20"}, Name: "Done", ProjectID: "1", SectionOrder: 2})
+	client.Section.cache.store(Section{Entity: Entity{ID: "10"}, Name: "Todo", ProjectID: "1", SectionOrder: 1})
+	for _, item := range []Item{
+		{Entity: Entity{ID: "100"}, ProjectID: "1", SectionID: "10", ChildOrder: 2},
+		{Entity: Entity{ID: "101"}, ProjectID: "1", SectionID: "10", ChildOrder: 1},
+		{Entity: Entity{ID: "102"}, ProjectID: "1", SectionID: "10", ParentID: "100", ChildOrder: 2},
+		{Entity: Entity{ID: "103"}, ProjectID: "1", SectionID: "10", ParentID: "100", ChildOrder: 1},
+		{Entity: Entity{ID: "104"}, ProjectID: "1", SectionID: "10", ParentID: "103", ChildOrder: 1},
+		{Entity: Entity{ID: "200"}, ProjectID: "1", ChildOrder: 1},
+		{Entity: Entity{ID: "201"}, ProjectID: "1", ParentID: "999", ChildOrder: 2},
+		{Entity: Entity{ID: "300"}, ProjectID: "2", ChildOrder: 1},
+	} {
+		client.Item.cache.store(item)
+	}
+
+	tree := client.Project.Tree("1")
+	if tree.Project.Name != "Work" {
+		t.Errorf("Expect %s, but got %s", "Work", tree.Project.Name)
+	}
+	if len(tree.Sections) != 2 || tree.Sections[0].Section.ID != "10" || tree.Sections[1].Section.ID != "20" {
+		t.Fatalf("Unexpect sections: %v", tree.Sections)
+	}
+	todo := tree.Sections[0].Items
+	if len(todo) != 2 || todo[0].Item.ID != "101" || todo[1].Item.ID != "100" {
+		t.Fatalf("Unexpect items: %v", todo)
+	}
+	subtasks := todo[1].Subtasks
+	if len(subtasks) != 2 || subtasks[0].Item.ID != "103" || subtasks[1].Item.ID != "102" {
+		t.Fatalf("Unexpect subtasks: %v", subtasks)
+	}
+	if len(subtasks[0].Subtasks) != 1 || subtasks[0].Subtasks[0].Item.ID != "104" {
+		t.Errorf("Unexpect subtasks: %v", subtasks[0].Subtasks)
+	}
+	if len(tree.Sections[1].Items) != 0 {
+		t.Errorf("Unexpect items: %v", tree.Sections[1].Items)
+	}
+	if len(tree.Items) != 2 || tree.Items[0].Item.ID != "200" || tree.Items[1].Item.ID != "201" {
+		t.Errorf("Unexpect items: %v", tree.Items)
+	}
+}
+
+func TestProjectClient_TreeCycle(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("Unexpect request: %s", r.URL.Path)
+	})
+	defer teardown()
+
+	client.Item.cache.store(Item{Entity: Entity{ID: "1"}, ProjectID: "1", ParentID: "2"})
+	client.Item.cache.store(Item{Entity: Entity{ID: "2"}, ProjectID: "1", ParentID: "1"})
+
+	tree := client.Project.Tree("1")
+	if len(tree.Items) != 1 || len(tree.Items[0].Subtasks) != 1 {
+		t.Errorf("Unexpect items: %v", tree.Items)
+	}
+}
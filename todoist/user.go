@@ -0,0 +1,122 @@
+package todoist
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// User is the user of the token with the settings.
+// AutoReminder is the minutes before the due of the reminder added automatically.
+type User struct {
+	ID           ID     `json:"id"`
+	Email        string `json:"email"`
+	FullName     string `json:"full_name"`
+	InboxProject ID     `json:"inbox_project"`
+	AutoReminder int    `json:"auto_reminder"`
+	TzInfo       TzInfo `json:"tz_info"`
+	// StartDay is the first day of the week, from 1 (Monday) to 7 (Sunday).
+	StartDay int `json:"start_day"`
+	// NextWeek is the day "next week" refers to, from 1 (Monday) to 7 (Sunday).
+	NextWeek int `json:"next_week"`
+	// TimeFormat is TimeFormat24Hour or TimeFormat12Hour.
+	TimeFormat int `json:"time_format"`
+	// DateFormat is DateFormatDayMonth or DateFormatMonthDay.
+	DateFormat int     `json:"date_format"`
+	IsPremium  IntBool `json:"is_premium"`
+}
+
+const (
+	TimeFormat24Hour = 0
+	TimeFormat12Hour = 1
+)
+
+const (
+	DateFormatDayMonth = 0
+	DateFormatMonthDay = 1
+)
+
+// StartWeekday returns the first day of the week. It is Monday if StartDay is not set.
+func (u User) StartWeekday() time.Weekday {
+	return isoWeekday(u.StartDay)
+}
+
+// NextWeekday returns the day "next week" refers to. It is Monday if NextWeek is not set.
+func (u User) NextWeekday() time.Weekday {
+	return isoWeekday(u.NextWeek)
+}
+
+// isoWeekday converts the day from 1 (Monday) to 7 (Sunday) into time.Weekday.
+func isoWeekday(day int) time.Weekday {
+	if day < 1 || day > 7 {
+		return time.Monday
+	}
+	return time.Weekday(day % 7)
+}
+
+// TzInfo is the timezone of the user.
+type TzInfo struct {
+	Timezone  string `json:"timezone"`
+	GmtString string `json:"gmt_string"`
+	Hours     int    `json:"hours"`
+	Minutes   int    `json:"minutes"`
+	IsDst     int    `json:"is_dst"`
+}
+
+// Location returns the location of the user's timezone.
+// It falls back to the fixed offset if the timezone is unknown to the system.
+func (u User) Location() (*time.Location, error) {
+	if len(u.TzInfo.Timezone) == 0 {
+		return nil, errors.New("user has no timezone")
+	}
+	loc, err := time.LoadLocation(u.TzInfo.Timezone)
+	if err != nil {
+		offset := (u.TzInfo.Hours*60 + u.TzInfo.Minutes) * 60
+		return time.FixedZone(u.TzInfo.Timezone, offset), nil
+	}
+	return loc, nil
+}
+
+// User returns a copy of the user of the token with the settings, or nil before the user is synced.
+func (c *Client) User() *User {
+	return c.user.get()
+}
+
+type userCache struct {
+	user   *User
+	mu     sync.RWMutex
+	notify *notifier
+}
+
+// get returns a copy of the cached user, or nil before the first sync.
+func (c *userCache) get() *User {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.user == nil {
+		return nil
+	}
+	res := *c.user
+	return &res
+}
+
+func (c *userCache) store(user User) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("user")
+	c.user = &user
+}
+
+// reset sets the cached user, or clears it if nil.
+func (c *userCache) reset(user *User) {
+	defer c.notify.flush()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notify.record("user")
+	if user == nil {
+		c.user = nil
+		return
+	}
+	res := *user
+	c.user = &res
+}
@@ -0,0 +1,51 @@
+package todoist
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestClient_User(t *testing.T) {
+	client, teardown := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"sync_token": "next-token", "user": {
+			"id": 1, "email": "alice@example.com", "full_name": "Alice", "inbox_project": 10,
+			"auto_reminder": 30, "start_day": 7, "next_week": 1, "time_format": 1, "date_format": 1,
+			"is_premium": true,
+			"tz_info": {"timezone": "Asia/Tokyo", "gmt_string": "+09:00", "hours": 9, "minutes": 0, "is_dst": 0}}}`))
+	})
+	defer teardown()
+	client.SetSyncToken("token")
+	if user := client.User(); user != nil {
+		t.Fatalf("Expect nil before sync, but got %v", user)
+	}
+	if err := client.Sync(context.Background(), []Command{}); err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+
+	user := client.User()
+	if user == nil {
+		t.Fatal("Expect user, but got nil")
+	}
+	if user.FullName != "Alice" || user.InboxProject != "10" || user.AutoReminder != 30 || !user.IsPremium.Bool() {
+		t.Errorf("Unexpect user: %v", user)
+	}
+	if user.TimeFormat != TimeFormat12Hour || user.DateFormat != DateFormatMonthDay {
+		t.Errorf("Unexpect formats: %d, %d", user.TimeFormat, user.DateFormat)
+	}
+	if user.StartWeekday() != time.Sunday || user.NextWeekday() != time.Monday {
+		t.Errorf("Unexpect weekdays: %s, %s", user.StartWeekday(), user.NextWeekday())
+	}
+	if user.TzInfo.Timezone != "Asia/Tokyo" {
+		t.Errorf("Expect %s, but got %s", "Asia/Tokyo", user.TzInfo.Timezone)
+	}
+	if (User{}).StartWeekday() != time.Monday {
+		t.Errorf("Expect %s, but got %s", time.Monday, (User{}).StartWeekday())
+	}
+
+	user.FullName = "Bob"
+	if client.User().FullName != "Alice" {
+		t.Error("Expect the cached user not to be modified")
+	}
+}
@@ -0,0 +1,163 @@
+package todoist
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	EventItemAdded         = "item:added"
+	EventItemUpdated       = "item:updated"
+	EventItemDeleted       = "item:deleted"
+	EventItemCompleted     = "item:completed"
+	EventItemUncompleted   = "item:uncompleted"
+	EventNoteAdded         = "note:added"
+	EventNoteUpdated       = "note:updated"
+	EventNoteDeleted       = "note:deleted"
+	EventProjectAdded      = "project:added"
+	EventProjectUpdated    = "project:updated"
+	EventProjectDeleted    = "project:deleted"
+	EventProjectArchived   = "project:archived"
+	EventProjectUnarchived = "project:unarchived"
+	EventSectionAdded      = "section:added"
+	EventSectionUpdated    = "section:updated"
+	EventSectionDeleted    = "section:deleted"
+	EventSectionArchived   = "section:archived"
+	EventSectionUnarchived = "section:unarchived"
+	EventLabelAdded        = "label:added"
+	EventLabelDeleted      = "label:deleted"
+	EventLabelUpdated      = "label:updated"
+	EventFilterAdded       = "filter:added"
+	EventFilterDeleted     = "filter:deleted"
+	EventFilterUpdated     = "filter:updated"
+	EventReminderFired     = "reminder:fired"
+)
+
+const webhookSignatureHeader = "X-Todoist-Hmac-SHA256"
+
+// WebhookEvent is a notification sent by the webhooks of the app.
+// Data is the resource of the event, decoded by Item, Project and so on depending on the event name.
+type WebhookEvent struct {
+	Name      string          `json:"event_name"`
+	UserID    ID              `json:"user_id"`
+	Data      json.RawMessage `json:"event_data"`
+	Initiator Collaborator    `json:"initiator"`
+	Version   string          `json:"version"`
+}
+
+// WebhookSignatureError is returned when the signature of the webhook does not match the client secret.
+type WebhookSignatureError struct {
+	Signature string
+}
+
+func (e *WebhookSignatureError) Error() string {
+	return fmt.Sprintf("invalid webhook signature: %s", e.Signature)
+}
+
+// ParseWebhook verifies the signature of the webhook request with the client secret of the app, then decodes it.
+// The body of the request is restored, so that the caller can read it again.
+func ParseWebhook(r *http.Request, clientSecret string) (*WebhookEvent, error) {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	signature := r.Header.Get(webhookSignatureHeader)
+	if !validWebhookSignature(body, signature, clientSecret) {
+		return nil, &WebhookSignatureError{Signature: signature}
+	}
+	var out WebhookEvent
+	if err = json.Unmarshal(body, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+func validWebhookSignature(body []byte, signature, clientSecret string) bool {
+	actual, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(clientSecret))
+	mac.Write(body)
+	return hmac.Equal(actual, mac.Sum(nil))
+}
+
+func (e WebhookEvent) decode(resource string, out interface{}) error {
+	if !strings.HasPrefix(e.Name, resource+":") {
+		return fmt.Errorf("event %s does not have %s", e.Name, resource)
+	}
+	return json.Unmarshal(e.Data, out)
+}
+
+// Item returns the item of item:* events.
+func (e WebhookEvent) Item() (*Item, error) {
+	var out Item
+	if err := e.decode("item", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Note returns the note of note:* events.
+func (e WebhookEvent) Note() (*Note, error) {
+	var out Note
+	if err := e.decode("note", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Project returns the project of project:* events.
+func (e WebhookEvent) Project() (*Project, error) {
+	var out Project
+	if err := e.decode("project", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Section returns the section of section:* events.
+func (e WebhookEvent) Section() (*Section, error) {
+	var out Section
+	if err := e.decode("section", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Label returns the label of label:* events.
+func (e WebhookEvent) Label() (*Label, error) {
+	var out Label
+	if err := e.decode("label", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Filter returns the filter of filter:* events.
+func (e WebhookEvent) Filter() (*Filter, error) {
+	var out Filter
+	if err := e.decode("filter", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// Reminder returns the reminder of reminder:* events.
+func (e WebhookEvent) Reminder() (*Reminder, error) {
+	var out Reminder
+	if err := e.decode("reminder", &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
@@ -0,0 +1,50 @@
+package todoist
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newWebhookRequest(body, secret string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req.Header.Set("X-Todoist-Hmac-SHA256", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestParseWebhook(t *testing.T) {
+	body := `{"event_name": "item:added", "user_id": 1, "event_data": {"id": 100, "content": "Buy milk", "project_id": 10}, "version": "8"}`
+	event, err := ParseWebhook(newWebhookRequest(body, "secret"), "secret")
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if event.Name != EventItemAdded {
+		t.Errorf("Expect %s, but got %s", EventItemAdded, event.Name)
+	}
+	item, err := event.Item()
+	if err != nil {
+		t.Fatalf("Unexpect error: %s", err)
+	}
+	if item.ID != "100" || item.Content != "Buy milk" || item.ProjectID != "10" {
+		t.Errorf("Unexpect item: %v", item)
+	}
+	if _, err = event.Project(); err == nil {
+		t.Error("Expect error to decode a project from an item event, but got nil")
+	}
+}
+
+func TestParseWebhook_InvalidSignature(t *testing.T) {
+	body := `{"event_name": "item:added", "event_data": {}}`
+	_, err := ParseWebhook(newWebhookRequest(body, "other"), "secret")
+	var signatureErr *WebhookSignatureError
+	if !errors.As(err, &signatureErr) {
+		t.Errorf("Expect *WebhookSignatureError, but got %v", err)
+	}
+}